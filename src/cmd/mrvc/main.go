@@ -2,34 +2,53 @@ package main
 
 import (
 	"MultiRepoVC/src/internal/commands"
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
 	"fmt"
 	"os"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("No command provided.")
-		fmt.Println("Use 'mrvc help' to see available commands.")
-		return
+		fmt.Fprintln(os.Stderr, "No command provided.")
+		fmt.Fprintln(os.Stderr, "Use 'mrvc help' to see available commands.")
+		os.Exit(commands.ExitUsage)
 	}
 
 	cmdName := os.Args[1]
 
-	if cmdName == "help" {
-		commands.Global.List()
-		return
+	// Aliases come from the config of the repository we are standing in
+	// (if any) and the user and system config. A broken config file must
+	// not lock the user out of `mrvc config`, so commands run without
+	// aliases and with default settings.
+	config, err := v1.LoadConfig(fs.GetCurrentDir())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: ignoring config, using defaults and no aliases:", err)
+	} else {
+		commands.Global.LoadAliases(config)
 	}
 
-	cmd, err := commands.Global.Get(cmdName)
+	cmd, aliasArgs, err := commands.Global.Get(cmdName)
 	if err != nil {
-		fmt.Println(err.Error())
-		fmt.Println()
-		fmt.Println("Use 'mrvc help' to see available commands.")
-		return
+		fmt.Fprintln(os.Stderr, err.Error())
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Use 'mrvc help' to see available commands.")
+		os.Exit(commands.ExitCode(err))
 	}
 
 	base := commands.BaseCommand{}
-	if err := base.Run(cmd, os.Args[2:]); err != nil {
-		fmt.Println("Error:", err)
+	if err := base.Run(cmd, append(aliasArgs, os.Args[2:]...)); err != nil {
+		fail(err)
+	}
+}
+
+// fail prints err to stderr, unless it is an ExitError without a
+// message, and exits with the status ExitCode assigns to it.
+func fail(err error) {
+	var exitErr *commands.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
 	}
+	os.Exit(commands.ExitCode(err))
 }
@@ -3,21 +3,165 @@ package commands
 import (
 	"MultiRepoVC/src/internal/utils/arg"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // BaseCommand Template method providing common behavior
 type BaseCommand struct{}
 
 func (b *BaseCommand) Run(cmd Command, args []string) error {
-	parsed := arg.ParseArgs(args)
+	if wantsHelp(args) {
+		printHelp(cmd)
+		return nil
+	}
+
+	specs := parserSpecs(cmd)
 
-	// Validate required args
-	for _, req := range cmd.RequiredArgs() {
-		values, exists := parsed[req]
-		if !exists || len(values) == 0 {
-			return fmt.Errorf("missing required argument: --%s", req)
+	if grouped, ok := cmd.(GroupedCommand); ok {
+		shared, groups := arg.ParseGroups(args, grouped.GroupKey(), specs)
+		if len(groups) > 0 {
+			return b.runGroups(grouped, shared, groups)
 		}
 	}
 
+	parsed := arg.ParseArgsWithSpecs(args, specs)
+
+	if err := validateArgs(cmd, parsed); err != nil {
+		return err
+	}
+	applyDefaults(cmd, parsed)
+
 	return cmd.ExecuteCommand(parsed)
 }
+
+// runGroups merges shared values into every group (group values win),
+// validates each group and hands them all to the command at once.
+func (b *BaseCommand) runGroups(cmd GroupedCommand, shared map[string][]string, groups []map[string][]string) error {
+	merged := make([]map[string][]string, 0, len(groups))
+
+	for _, group := range groups {
+		m := make(map[string][]string)
+		for k, v := range shared {
+			m[k] = v
+		}
+		for k, v := range group {
+			m[k] = v
+		}
+
+		if err := validateArgs(cmd, m); err != nil {
+			return fmt.Errorf("%w (--%s %s)", err, cmd.GroupKey(), strings.Join(m[cmd.GroupKey()], " "))
+		}
+		applyDefaults(cmd, m)
+		merged = append(merged, m)
+	}
+
+	return cmd.ExecuteGroups(merged)
+}
+
+// wantsHelp reports whether --help or -h appears before a bare "--".
+func wantsHelp(args []string) bool {
+	for _, a := range args {
+		if a == arg.Rest {
+			return false
+		}
+		if a == "--help" || a == "-h" {
+			return true
+		}
+	}
+	return false
+}
+
+// parserSpecs translates the command's ArgSpecs for the parser.
+func parserSpecs(cmd Command) []arg.Spec {
+	var specs []arg.Spec
+	for _, s := range cmd.Args() {
+		specs = append(specs, arg.Spec{
+			Name:  s.Name,
+			Short: s.Short,
+			Bool:  s.Type == ArgBool,
+			Multi: s.Repeatable,
+		})
+	}
+	return specs
+}
+
+// validateArgs checks parsed against the command's ArgSpecs: every flag
+// must be declared, value flags need a value of their type, only
+// repeatable flags may be given more than once and required flags must
+// be present.
+func validateArgs(cmd Command, parsed map[string][]string) error {
+	specs := make(map[string]ArgSpec)
+	var names []string
+	for _, s := range cmd.Args() {
+		specs[s.Name] = s
+		names = append(names, s.Name)
+		if s.Short != "" {
+			names = append(names, s.Short)
+		}
+	}
+
+	for key, values := range parsed {
+		if key == "positional" || key == arg.Rest {
+			continue
+		}
+
+		spec, ok := specs[key]
+		if !ok {
+			msg := fmt.Sprintf("unknown flag for %s: %s", cmd.Name(), flagName(key))
+			if s := suggest(key, names); s != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", flagName(s))
+			}
+			return newUsageError(msg)
+		}
+
+		switch {
+		case spec.Type == ArgBool:
+			for _, v := range values {
+				if v != "true" {
+					return &UsageError{Err: fmt.Errorf("%s takes no value", flagName(key))}
+				}
+			}
+		case len(values) == 0:
+			return &UsageError{Err: fmt.Errorf("%s needs a value", flagName(key))}
+		case len(values) > 1 && !spec.Repeatable:
+			return &UsageError{Err: fmt.Errorf("%s given more than once", flagName(key))}
+		}
+
+		if spec.Type == ArgInt {
+			for _, v := range values {
+				if _, err := strconv.Atoi(v); err != nil {
+					return &UsageError{Err: fmt.Errorf("%s needs a number, got %q", flagName(key), v)}
+				}
+			}
+		}
+	}
+
+	for _, s := range cmd.Args() {
+		if !s.Required {
+			continue
+		}
+		if values, exists := parsed[s.Name]; !exists || len(values) == 0 {
+			return &UsageError{Err: fmt.Errorf("missing required argument: %s", flagName(s.Name))}
+		}
+	}
+	return nil
+}
+
+// applyDefaults stores the default of every absent flag that has one.
+func applyDefaults(cmd Command, parsed map[string][]string) {
+	for _, s := range cmd.Args() {
+		if _, ok := parsed[s.Name]; !ok && s.Default != "" && s.Type != ArgBool {
+			parsed[s.Name] = []string{s.Default}
+		}
+	}
+}
+
+// flagName spells key the way it is typed: -x for a single letter,
+// --name otherwise.
+func flagName(key string) string {
+	if len(key) == 1 {
+		return "-" + key
+	}
+	return "--" + key
+}
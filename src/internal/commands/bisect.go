@@ -0,0 +1,177 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/arg"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Exit codes of a `bisect run` script besides 0 (good) and 1–127 (bad).
+const (
+	bisectSkipCode  = 125 // the commit cannot be tested
+	bisectAbortCode = 128 // this and above stop the run
+)
+
+type BisectCommand struct {
+	BaseCommand
+}
+
+func (c *BisectCommand) Name() string { return "bisect" }
+func (c *BisectCommand) Description() string {
+	return "Binary-searches the first-parent history for the commit that introduced a bug: " +
+		"bisect start <bad> <good>, bisect good|bad|skip [<rev>], bisect reset, " +
+		"bisect run [--] <script> [args] (exit 0 = good, 125 = skip, 1-127 = bad)."
+}
+
+func (c *BisectCommand) Args() []ArgSpec { return nil }
+func (c *BisectCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+	if len(positional) == 0 {
+		return newUsageError("missing subcommand: start, good, bad, skip, reset or run")
+	}
+
+	vc := v1.New()
+	sub, args := positional[0], positional[1:]
+
+	switch sub {
+	case "start":
+		if len(args) != 2 {
+			return newUsageError("usage: mrvc bisect start <bad> <good>")
+		}
+		bad, err := vc.ResolveRevision(args[0])
+		if err != nil {
+			return err
+		}
+		good, err := vc.ResolveRevision(args[1])
+		if err != nil {
+			return err
+		}
+
+		status, err := vc.BisectStart(bad, good)
+		if err != nil {
+			return err
+		}
+		return printBisect(vc, status)
+
+	case "good", "bad", "skip":
+		rev := "HEAD"
+		if len(args) > 1 {
+			return newUsageError("usage: mrvc bisect " + sub + " [<rev>]")
+		}
+		if len(args) == 1 {
+			rev = args[0]
+		}
+
+		hash, err := vc.ResolveRevision(rev)
+		if err != nil {
+			return err
+		}
+
+		status, err := vc.BisectMark(sub, hash)
+		if err != nil {
+			return err
+		}
+		return printBisect(vc, status)
+
+	case "reset":
+		if err := vc.BisectReset(); err != nil {
+			return err
+		}
+		head, err := vc.ResolveRevision("HEAD")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("HEAD is now at %s\n", v1.ShortHash(head))
+		return nil
+
+	case "run":
+		script := append(args, p[arg.Rest]...)
+		if len(script) == 0 {
+			return newUsageError("usage: mrvc bisect run [--] <script> [args]")
+		}
+		return c.run(vc, script)
+
+	default:
+		return newUsageError("unknown bisect subcommand: " + sub)
+	}
+}
+
+// run tests the checked out commit with script until the first bad
+// commit is found. Like foreach, a single argument runs through the
+// shell and several are executed as they are.
+func (c *BisectCommand) run(vc *v1.VersionControlV1, script []string) error {
+	for {
+		head, err := vc.ResolveRevision("HEAD")
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("running %s\n", strings.Join(script, " "))
+
+		cmd := scriptCommand(script)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		code := 0
+		var exitErr *exec.ExitError
+		if err := cmd.Run(); errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else if err != nil {
+			return fmt.Errorf("bisect run failed: %w", err)
+		}
+
+		verdict := "bad"
+		switch {
+		case code == 0:
+			verdict = "good"
+		case code == bisectSkipCode:
+			verdict = "skip"
+		case code < 0 || code >= bisectAbortCode:
+			return fmt.Errorf("bisect run failed: script exited with %d on %s", code, v1.ShortHash(head))
+		}
+
+		status, err := vc.BisectMark(verdict, head)
+		if err != nil {
+			return err
+		}
+		if err := printBisect(vc, status); err != nil {
+			return err
+		}
+		if status.Done() {
+			return nil
+		}
+	}
+}
+
+func printBisect(vc *v1.VersionControlV1, status v1.BisectStatus) error {
+	switch {
+	case status.Culprit != "":
+		fmt.Printf("%s is the first bad commit\n", status.Culprit)
+		entries, err := vc.Log(status.Culprit, v1.LogFilter{MaxCount: 1})
+		if err != nil {
+			return err
+		}
+		printLog(entries, nil)
+
+	case len(status.Skipped) > 0:
+		fmt.Println("There are only 'skip'ped commits left to test.")
+		fmt.Println("The first bad commit could be any of:")
+		for _, h := range status.Skipped {
+			fmt.Println(h)
+		}
+
+	default:
+		fmt.Printf("Bisecting: %d revisions left to test after this (roughly %d steps)\n",
+			status.Remaining, status.Steps)
+		fmt.Printf("HEAD is now at %s\n", v1.ShortHash(status.Current))
+	}
+	return nil
+}
+
+func init() {
+	Global.Register(&BisectCommand{})
+}
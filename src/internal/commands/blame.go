@@ -0,0 +1,144 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/time"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type BlameCommand struct {
+	BaseCommand
+}
+
+func (c *BlameCommand) Name() string { return "blame" }
+func (c *BlameCommand) Description() string {
+	return "Shows the commit that last changed each line: blame <path> [<rev>] [-L start,end] [--porcelain]."
+}
+
+func (c *BlameCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "L", Type: ArgString, Description: "only blame the lines start,end"},
+		{Name: "porcelain", Type: ArgBool, Description: "print machine-readable output"},
+	}
+}
+
+func (c *BlameCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+	lineRange := ""
+	if l, ok := p["L"]; ok {
+		lineRange = l[0]
+	}
+
+	if len(positional) == 0 || len(positional) > 2 {
+		return newUsageError("usage: mrvc blame <path> [<rev>]")
+	}
+	path := positional[0]
+
+	rev := "HEAD"
+	if len(positional) == 2 {
+		rev = positional[1]
+	}
+
+	vc := v1.New()
+	hash, err := vc.ResolveRevision(rev)
+	if err != nil {
+		return err
+	}
+
+	lines, err := vc.Blame(path, hash)
+	if err != nil {
+		return err
+	}
+
+	if lineRange != "" {
+		start, end, err := parseLineRange(lineRange, len(lines))
+		if err != nil {
+			return err
+		}
+		lines = lines[start-1 : end]
+	}
+
+	if _, ok := p["porcelain"]; ok {
+		printBlamePorcelain(path, lines)
+	} else {
+		printBlame(lines)
+	}
+	return nil
+}
+
+// parseLineRange parses -L "start,end" (1-based, inclusive). An omitted
+// end runs to the last line.
+func parseLineRange(s string, total int) (int, int, error) {
+	startText, endText, _ := strings.Cut(s, ",")
+
+	start, err := strconv.Atoi(startText)
+	if err != nil || start < 1 {
+		return 0, 0, newUsageError("invalid -L range: " + s)
+	}
+
+	end := total
+	if endText != "" {
+		if end, err = strconv.Atoi(endText); err != nil || end < start {
+			return 0, 0, newUsageError("invalid -L range: " + s)
+		}
+	}
+
+	if start > total || end > total {
+		return 0, 0, fmt.Errorf("file has only %d lines", total)
+	}
+	return start, end, nil
+}
+
+func printBlame(lines []v1.BlameLine) {
+	if len(lines) == 0 {
+		return
+	}
+
+	authorWidth := 0
+	for _, l := range lines {
+		authorWidth = max(authorWidth, len(l.Author))
+	}
+	numberWidth := len(strconv.Itoa(lines[len(lines)-1].Line))
+
+	for _, l := range lines {
+		fmt.Printf("%s (%-*s %s %*d) %s\n",
+			v1.ShortHash(l.Commit), authorWidth, l.Author, time.FormatISO(l.Time),
+			numberWidth, l.Line, l.Content)
+	}
+}
+
+// printBlamePorcelain prints Git's porcelain format: a header per line
+// ("<hash> <orig-line> <final-line>", plus the group size on the first
+// line of a run from one commit), the commit details the first time a
+// commit appears, then the line prefixed with a tab.
+func printBlamePorcelain(path string, lines []v1.BlameLine) {
+	seen := make(map[string]bool)
+
+	for i, l := range lines {
+		if i == 0 || lines[i-1].Commit != l.Commit {
+			group := 1
+			for i+group < len(lines) && lines[i+group].Commit == l.Commit {
+				group++
+			}
+			fmt.Printf("%s %d %d %d\n", l.Commit, l.OrigLine, l.Line, group)
+		} else {
+			fmt.Printf("%s %d %d\n", l.Commit, l.OrigLine, l.Line)
+		}
+
+		if !seen[l.Commit] {
+			seen[l.Commit] = true
+			fmt.Printf("author %s\n", l.Author)
+			fmt.Printf("author-time %d\n", l.Time/1000)
+			fmt.Printf("summary %s\n", l.Summary)
+			fmt.Printf("filename %s\n", path)
+		}
+
+		fmt.Printf("\t%s\n", l.Content)
+	}
+}
+
+func init() {
+	Global.Register(&BlameCommand{})
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+)
+
+type CherryPickCommand struct {
+	BaseCommand
+}
+
+func (c *CherryPickCommand) Name() string { return "cherry-pick" }
+func (c *CherryPickCommand) Description() string {
+	return "Applies the changes of existing commits onto HEAD: cherry-pick [-x] <rev>... " +
+		"-x records the source commit. On conflicts use --continue, --skip or --abort."
+}
+
+func (c *CherryPickCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "x", Type: ArgBool, Description: "record the source commit in the message"},
+		{Name: "continue", Type: ArgBool, Description: "resume after resolving conflicts"},
+		{Name: "skip", Type: ArgBool, Description: "drop the conflicting commit and go on"},
+		{Name: "abort", Type: ArgBool, Description: "give up and restore the original HEAD"},
+	}
+}
+
+func (c *CherryPickCommand) ExecuteCommand(p map[string][]string) error {
+	vc := v1.New()
+	positional := p["positional"]
+
+	action := ""
+	for _, flag := range []string{"continue", "skip", "abort"} {
+		if _, ok := p[flag]; ok {
+			if action != "" {
+				return newUsageError("--continue, --skip and --abort are mutually exclusive")
+			}
+			action = flag
+		}
+	}
+	if action != "" && len(positional) > 0 {
+		return newUsageError("--" + action + " takes no revisions")
+	}
+
+	var err error
+	switch action {
+	case "continue":
+		err = vc.CherryPickContinue()
+	case "skip":
+		err = vc.CherryPickSkip()
+	case "abort":
+		if err := vc.CherryPickAbort(); err != nil {
+			return err
+		}
+		fmt.Println("Cherry-pick aborted")
+		return nil
+	default:
+		if len(positional) == 0 {
+			return newUsageError("cherry-pick needs at least one revision")
+		}
+
+		// Resolve everything up front so a typo picks nothing
+		commits := make([]string, 0, len(positional))
+		for _, rev := range positional {
+			hash, err := vc.ResolveRevision(rev)
+			if err != nil {
+				return err
+			}
+			commits = append(commits, hash)
+		}
+
+		_, recordOrigin := p["x"]
+		err = vc.CherryPick(commits, recordOrigin)
+	}
+	if err != nil {
+		return err
+	}
+
+	head, err := vc.ResolveRevision("HEAD")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("HEAD is now at %s\n", v1.ShortHash(head))
+	return nil
+}
+
+func init() {
+	Global.Register(&CherryPickCommand{})
+}
@@ -3,9 +3,49 @@ package commands
 type Command interface {
 	Name() string
 	Description() string
-	RequiredArgs() []string
-	OptionalArgs() []string
+
+	// Args declares every flag the command accepts. Flags not listed are
+	// rejected before ExecuteCommand runs.
+	Args() []ArgSpec
 
 	// ExecuteCommand parsed: key → []values
 	ExecuteCommand(parsed map[string][]string) error
 }
+
+// ArgType is the kind of value a flag takes.
+type ArgType string
+
+const (
+	ArgString ArgType = "string"
+	ArgInt    ArgType = "int"
+	ArgBool   ArgType = "bool" // takes no value; present means true
+)
+
+// ArgSpec describes one flag of a command. Parsed values are stored
+// under Name, also when the flag was given by its Short alias.
+type ArgSpec struct {
+	Name        string
+	Short       string // one-letter alias, used as -<Short>
+	Type        ArgType
+	Default     string // stored under Name when the flag is absent
+	Description string
+	Required    bool
+
+	// Repeatable flags collect every value up to the next flag and may
+	// be given more than once; others take exactly one value.
+	Repeatable bool
+}
+
+// GroupedCommand is implemented by commands that accept repeated argument
+// groups introduced by the same flag, e.g.
+//
+//	--repo a --message "m1" --repo b --message "m2"
+//
+// Values given before the first group are shared by every group.
+type GroupedCommand interface {
+	Command
+	GroupKey() string
+
+	// ExecuteGroups groups: one merged key → []values map per group
+	ExecuteGroups(groups []map[string][]string) error
+}
@@ -2,7 +2,10 @@ package commands
 
 import (
 	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
 	"errors"
+	"fmt"
 )
 
 type CommitCommand struct {
@@ -11,16 +14,95 @@ type CommitCommand struct {
 
 func (c *CommitCommand) Name() string { return "commit" }
 func (c *CommitCommand) Description() string {
-	return "Creates a new commit with a message and files."
+	return "Creates a new commit with a message and files. " +
+		"Repeat --repo <name|repo_id> --message ... --files ... to commit several nested repos at once. " +
+		"The author is --author \"Name <email>\" or MRVC_AUTHOR_NAME/MRVC_AUTHOR_EMAIL; " +
+		"--allow-anonymous commits without one."
 }
 
-func (c *CommitCommand) RequiredArgs() []string { return []string{"message"} }
-func (c *CommitCommand) OptionalArgs() []string { return []string{"author", "files"} }
+func (c *CommitCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "message", Short: "m", Type: ArgString, Required: true, Description: "commit message"},
+		{Name: "author", Type: ArgString, Description: "author as \"Name <email>\""},
+		{Name: "files", Type: ArgString, Repeatable: true, Description: "files to commit; plain arguments work too"},
+		{Name: "repo", Type: ArgString, Description: "nested repo (name or repo_id) the following flags apply to"},
+		{Name: "allow-anonymous", Type: ArgBool, Description: "commit without an author identity"},
+	}
+}
+
+func (c *CommitCommand) GroupKey() string { return "repo" }
 
 func (c *CommitCommand) ExecuteCommand(p map[string][]string) error {
+	message, author, files, err := commitArgs(p)
+	if err != nil {
+		return err
+	}
+
+	vc := v1.New()
+	identity, err := commitAuthor(vc, author, p)
+	if err != nil {
+		return err
+	}
+	return vc.Commit(message, identity, files)
+}
+
+// ExecuteGroups commits every --repo group into its own repository.
+// All repos are resolved before anything is written, so a typo in the
+// last group does not leave the earlier repos committed.
+func (c *CommitCommand) ExecuteGroups(groups []map[string][]string) error {
+	repos, err := v1.DiscoverRepos(fs.GetCurrentDir())
+	if err != nil {
+		return err
+	}
+
+	type job struct {
+		repo    v1.RepoInfo
+		message string
+		author  model.Identity
+		files   []string
+	}
+
+	jobs := make([]job, 0, len(groups))
+	for _, g := range groups {
+		if len(g["repo"]) != 1 {
+			return newUsageError("--repo takes exactly one repository name or repo_id")
+		}
+
+		repo, err := v1.FindRepo(repos, g["repo"][0])
+		if err != nil {
+			return err
+		}
+
+		message, author, files, err := commitArgs(g)
+		if err != nil {
+			return fmt.Errorf("%w (--repo %s)", err, repo.Metadata.Name)
+		}
+
+		identity, err := commitAuthor(v1.NewAt(repo.Path), author, g)
+		if err != nil {
+			return fmt.Errorf("%w (--repo %s)", err, repo.Metadata.Name)
+		}
+
+		jobs = append(jobs, job{repo: repo, message: message, author: identity, files: files})
+	}
+
+	for _, j := range jobs {
+		fmt.Printf("[%s] %s\n", j.repo.Metadata.Name, j.repo.RelPath)
+
+		vc := v1.NewAt(j.repo.Path)
+		if err := vc.Commit(j.message, j.author, j.files); err != nil {
+			return fmt.Errorf("commit in %s failed: %w", j.repo.Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// commitArgs extracts message, author and files from parsed arguments.
+func commitArgs(p map[string][]string) (string, string, []string, error) {
 	message := p["message"][0]
 
-	author := "unknown"
+	author := ""
 	if a, ok := p["author"]; ok && len(a) > 0 {
 		author = a[0]
 	}
@@ -34,11 +116,38 @@ func (c *CommitCommand) ExecuteCommand(p map[string][]string) error {
 	}
 
 	if len(files) == 0 {
-		return errors.New("no files specified")
+		return "", "", nil, newUsageError("no files specified")
 	}
 
-	vc := v1.New()
-	return vc.Commit(message, author, files)
+	return message, author, files, nil
+}
+
+// commitAuthor resolves the author of a commit: --author, else the
+// configured identity, else AnonymousName if --allow-anonymous (or the
+// commit.allowAnonymous setting) is set.
+func commitAuthor(vc *v1.VersionControlV1, author string, p map[string][]string) (model.Identity, error) {
+	if author != "" {
+		return v1.ParseIdentity(author), nil
+	}
+
+	identity, err := vc.Identity()
+	if !errors.Is(err, v1.ErrNoIdentity) {
+		return identity, err
+	}
+
+	config, cerr := vc.Config()
+	if cerr != nil {
+		return identity, cerr
+	}
+	anonymous, cerr := config.Bool("commit.allowAnonymous", false)
+	if cerr != nil {
+		return identity, cerr
+	}
+
+	if _, ok := p["allow-anonymous"]; ok || anonymous {
+		return v1.NewIdentity(v1.AnonymousName, ""), nil
+	}
+	return identity, err
 }
 
 func init() {
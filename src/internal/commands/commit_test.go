@@ -0,0 +1,144 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newWorkspace creates a root repo holding the nested repos "auth" and
+// "billing", each with a file a.txt, and makes it the working directory.
+func newWorkspace(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("MRVC_CONFIG_SYSTEM", filepath.Join(dir, "system-config"))
+	t.Setenv("MRVC_CONFIG_GLOBAL", filepath.Join(dir, "global-config"))
+	t.Setenv("MRVC_AUTHOR_NAME", "tester")
+	t.Setenv("MRVC_AUTHOR_EMAIL", "tester@example.com")
+
+	root := filepath.Join(dir, "platform")
+	for _, repo := range []string{"", "auth", "billing"} {
+		path := filepath.Join(root, repo)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		name := repo
+		if name == "" {
+			name = "platform"
+		}
+		if err := v1.NewAt(path).Init(name, "tester"); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "a.txt"), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Chdir(root)
+	return root
+}
+
+// headMessage returns the message of the HEAD commit of the repo at
+// path, or "" if it has no commits.
+func headMessage(t *testing.T, path string) string {
+	t.Helper()
+
+	vc := v1.NewAt(path)
+	head, err := vc.ResolveRevision("HEAD")
+	if err != nil {
+		return ""
+	}
+	entries, err := vc.Log(head, v1.LogFilter{MaxCount: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries[0].Commit.Message
+}
+
+func TestCommitGroups(t *testing.T) {
+	root := newWorkspace(t)
+
+	groups := []map[string][]string{
+		{"repo": {"auth"}, "message": {"fix auth"}, "files": {"a.txt"}},
+		{"repo": {"billing"}, "message": {"fix billing"}, "positional": {"a.txt"}},
+	}
+	if err := (&CommitCommand{}).ExecuteGroups(groups); err != nil {
+		t.Fatal(err)
+	}
+
+	for repo, want := range map[string]string{"auth": "fix auth", "billing": "fix billing", ".": ""} {
+		if got := headMessage(t, filepath.Join(root, repo)); got != want {
+			t.Errorf("HEAD of %s has message %q, want %q", repo, got, want)
+		}
+	}
+}
+
+func TestCommitGroupsByRepoID(t *testing.T) {
+	root := newWorkspace(t)
+
+	meta, err := v1.ReadMetadata(filepath.Join(root, "billing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := []map[string][]string{
+		{"repo": {meta.RepoID}, "message": {"by id"}, "files": {"a.txt"}},
+	}
+	if err := (&CommitCommand{}).ExecuteGroups(groups); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := headMessage(t, filepath.Join(root, "billing")); got != "by id" {
+		t.Errorf("HEAD of billing has message %q, want %q", got, "by id")
+	}
+}
+
+// A bad group anywhere fails the whole command before any repo is
+// committed.
+func TestCommitGroupsResolveAllBeforeWriting(t *testing.T) {
+	tests := []struct {
+		name    string
+		second  map[string][]string
+		wantErr string
+	}{
+		{
+			name:    "unknown repo",
+			second:  map[string][]string{"repo": {"shipping"}, "message": {"m"}, "files": {"a.txt"}},
+			wantErr: "no repository named or identified by: shipping",
+		},
+		{
+			name:    "two repos in one group",
+			second:  map[string][]string{"repo": {"billing", "auth"}, "message": {"m"}, "files": {"a.txt"}},
+			wantErr: "--repo takes exactly one",
+		},
+		{
+			name:    "no files",
+			second:  map[string][]string{"repo": {"billing"}, "message": {"m"}},
+			wantErr: "no files specified (--repo billing)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newWorkspace(t)
+
+			groups := []map[string][]string{
+				{"repo": {"auth"}, "message": {"first"}, "files": {"a.txt"}},
+				tt.second,
+			}
+			err := (&CommitCommand{}).ExecuteGroups(groups)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ExecuteGroups() = %v, want error %q", err, tt.wantErr)
+			}
+
+			for _, repo := range []string{"auth", "billing"} {
+				if got := headMessage(t, filepath.Join(root, repo)); got != "" {
+					t.Errorf("%s was committed (%q) although a group was invalid", repo, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/fs"
+	"fmt"
+	"strings"
+)
+
+type ConfigCommand struct {
+	BaseCommand
+}
+
+func (c *ConfigCommand) Name() string { return "config" }
+func (c *ConfigCommand) Description() string {
+	return "Reads and writes settings: config get|set|unset|list [key] [value] " +
+		"[--system|--global|--local] [--type bool|int|duration] [--show-origin]. " +
+		"Local settings override global ones, which override system ones."
+}
+
+func (c *ConfigCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "system", Type: ArgBool, Description: "use the system config file"},
+		{Name: "global", Type: ArgBool, Description: "use the user's config file"},
+		{Name: "local", Type: ArgBool, Description: "use the repository's config file"},
+		{Name: "type", Type: ArgString, Description: "check and normalize values as bool, int or duration"},
+		{Name: "show-origin", Type: ArgBool, Description: "print the file each value comes from"},
+	}
+}
+
+func (c *ConfigCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+
+	typ := ""
+	if t, ok := p["type"]; ok {
+		typ = t[0]
+		if typ != "bool" && typ != "int" && typ != "duration" {
+			return newUsageError("unknown --type: " + typ + " (use bool, int or duration)")
+		}
+	}
+
+	scope, explicit, err := configScope(p)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) == 0 {
+		return newUsageError("missing subcommand: get, set, unset or list")
+	}
+	sub, args := positional[0], positional[1:]
+	_, showOrigin := p["show-origin"]
+	root := fs.GetCurrentDir()
+
+	switch sub {
+	case "get":
+		if len(args) != 1 {
+			return newUsageError("usage: mrvc config get <key>")
+		}
+		return c.get(root, args[0], typ, scope, explicit, showOrigin)
+
+	case "set":
+		if len(args) != 2 {
+			return newUsageError("usage: mrvc config set <key> <value>")
+		}
+		value, err := v1.NormalizeConfigValue(typ, args[1])
+		if err != nil {
+			return err
+		}
+		return v1.SetConfig(scope, root, args[0], value)
+
+	case "unset":
+		if len(args) != 1 {
+			return newUsageError("usage: mrvc config unset <key>")
+		}
+		return v1.UnsetConfig(scope, root, args[0])
+
+	case "list":
+		if len(args) != 0 {
+			return newUsageError("usage: mrvc config list")
+		}
+		return c.list(root, scope, explicit, showOrigin)
+	}
+
+	return &UsageError{Err: fmt.Errorf("unknown config subcommand: %s (use get, set, unset or list)", sub)}
+}
+
+// get prints the value of key: the winning one, or the one of scope if
+// a scope flag was given.
+func (c *ConfigCommand) get(root, key, typ string, scope v1.ConfigScope, explicit, showOrigin bool) error {
+	if err := v1.ValidateConfigKey(key); err != nil {
+		return err
+	}
+
+	entries, err := configEntries(root, scope, explicit)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if !strings.EqualFold(e.Key, key) {
+			continue
+		}
+
+		value, err := v1.NormalizeConfigValue(typ, e.Value)
+		if err != nil {
+			return fmt.Errorf("%s (%s): %w", key, e.Origin, err)
+		}
+
+		if showOrigin {
+			fmt.Printf("file:%s\t%s\n", e.Origin, value)
+		} else {
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", v1.ErrConfigKeyNotSet, key)
+}
+
+func (c *ConfigCommand) list(root string, scope v1.ConfigScope, explicit, showOrigin bool) error {
+	entries, err := configEntries(root, scope, explicit)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if showOrigin {
+			fmt.Printf("file:%s\t%s = %s\n", e.Origin, e.Key, e.Value)
+		} else {
+			fmt.Printf("%s = %s\n", e.Key, e.Value)
+		}
+	}
+	return nil
+}
+
+// configEntries returns the entries of scope's file when explicit, and
+// the merged configuration otherwise.
+func configEntries(root string, scope v1.ConfigScope, explicit bool) ([]v1.ConfigEntry, error) {
+	if explicit {
+		if _, err := v1.ConfigPath(scope, root); err != nil {
+			return nil, err
+		}
+		return v1.ReadConfigFile(scope, root)
+	}
+
+	config, err := v1.LoadConfig(root)
+	if err != nil {
+		return nil, err
+	}
+	return config.Entries(), nil
+}
+
+// configScope returns the scope chosen with --system, --global or
+// --local, and whether one was chosen at all. set and unset default to
+// the local scope.
+func configScope(p map[string][]string) (v1.ConfigScope, bool, error) {
+	var chosen []v1.ConfigScope
+	for _, s := range []v1.ConfigScope{v1.ScopeSystem, v1.ScopeGlobal, v1.ScopeLocal} {
+		if _, ok := p[string(s)]; ok {
+			chosen = append(chosen, s)
+		}
+	}
+
+	switch len(chosen) {
+	case 0:
+		return v1.ScopeLocal, false, nil
+	case 1:
+		return chosen[0], true, nil
+	}
+	return "", false, newUsageError("--system, --global and --local are mutually exclusive")
+}
+
+func init() {
+	Global.Register(&ConfigCommand{})
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+)
+
+type DiffCommand struct {
+	BaseCommand
+}
+
+func (c *DiffCommand) Name() string { return "diff" }
+func (c *DiffCommand) Description() string {
+	return "Shows changed files and nested repos: diff (HEAD vs working dir), diff <rev>, diff <from> <to>."
+}
+
+func (c *DiffCommand) Args() []ArgSpec { return nil }
+func (c *DiffCommand) ExecuteCommand(p map[string][]string) error {
+	revs := p["positional"]
+	if len(revs) > 2 {
+		return newUsageError("diff takes at most two revisions")
+	}
+
+	vc := v1.New()
+
+	from := "HEAD"
+	if len(revs) > 0 {
+		from = revs[0]
+	}
+
+	fromHash, err := vc.ResolveRevision(from)
+	if err != nil {
+		return err
+	}
+
+	toHash := ""
+	if len(revs) == 2 {
+		if toHash, err = vc.ResolveRevision(revs[1]); err != nil {
+			return err
+		}
+	}
+
+	result, err := vc.Diff(fromHash, toHash)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Files) == 0 && len(result.NestedRepos) == 0 {
+		fmt.Println("No differences.")
+	}
+
+	printChanges(result)
+
+	if len(result.Warnings) > 0 {
+		fmt.Println()
+	}
+	for _, w := range result.Warnings {
+		fmt.Println("Warning: " + w)
+	}
+
+	return nil
+}
+
+// printChanges lists changed files, then changed nested repos.
+func printChanges(result v1.DiffResult) {
+	for _, f := range result.Files {
+		if f.Rename != nil {
+			fmt.Println(f.Rename.String())
+			continue
+		}
+		fmt.Printf("%-9s %s\n", f.Status, f.Path)
+	}
+
+	if len(result.NestedRepos) > 0 {
+		if len(result.Files) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Nested repos:")
+		for _, n := range result.NestedRepos {
+			fmt.Println("  " + n.String())
+		}
+	}
+}
+
+func init() {
+	Global.Register(&DiffCommand{})
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"errors"
+	"strconv"
+)
+
+// Exit statuses of mrvc. Scripts may rely on them.
+const (
+	ExitOK      = 0
+	ExitFailure = 1   // any error not listed below
+	ExitUsage   = 2   // the command line is wrong: unknown command or flag, bad value
+	ExitDirty   = 3   // status --exit-code found local changes
+	ExitCorrupt = 128 // an object of the repository is missing or damaged
+)
+
+// ExitError is returned by commands that need the process to terminate
+// with a specific exit status. Err is printed, Code is passed to os.Exit.
+// A nil Err exits silently.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return "exit status " + strconv.Itoa(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// UsageError reports a command line that cannot be run as given.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+func newUsageError(msg string) error {
+	return &UsageError{Err: errors.New(msg)}
+}
+
+// ExitCode maps an error returned by a command to the exit status of
+// the process.
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	var usageErr *UsageError
+	var corruptErr *v1.CorruptError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.As(err, &corruptErr):
+		return ExitCorrupt
+	}
+	return ExitFailure
+}
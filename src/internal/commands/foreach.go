@@ -0,0 +1,251 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/arg"
+	"MultiRepoVC/src/internal/utils/fs"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+type ForeachCommand struct {
+	BaseCommand
+}
+
+func (c *ForeachCommand) Name() string { return "foreach" }
+func (c *ForeachCommand) Description() string {
+	return "Runs a command in every nested repository: mrvc foreach [--parallel N] [--filter glob] -- <cmd> [args]. " +
+		"A single argument runs through the shell, so pipes and $MRVC_* work; several are run as they are."
+}
+
+func (c *ForeachCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "parallel", Type: ArgInt, Default: "1", Description: "number of repos to run in at once"},
+		{Name: "filter", Type: ArgString, Description: "only repos whose name or path matches this glob"},
+		{Name: "include-root", Type: ArgBool, Description: "run in the root repository as well"},
+	}
+}
+
+// foreachResult is the outcome of running the command in one repo.
+type foreachResult struct {
+	repo     v1.RepoInfo
+	exitCode int
+	err      error // set when the command could not be started at all
+}
+
+func (c *ForeachCommand) ExecuteCommand(p map[string][]string) error {
+	// PRIMARY way: mrvc foreach [flags] -- cmd args
+	script := p[arg.Rest]
+
+	// FALLBACK: mrvc foreach cmd args (only possible without flags)
+	if len(script) == 0 {
+		script = p["positional"]
+	}
+
+	if len(script) == 0 {
+		return newUsageError("no command specified, use: mrvc foreach [flags] -- <cmd>")
+	}
+
+	parallel := 1
+	if v, ok := p["parallel"]; ok && len(v) > 0 {
+		n, err := strconv.Atoi(v[0])
+		if err != nil || n < 1 {
+			return newUsageError("--parallel must be a positive number")
+		}
+		parallel = n
+	}
+
+	repos, err := v1.DiscoverRepos(fs.GetCurrentDir())
+	if err != nil {
+		return err
+	}
+
+	// The first entry is the repo we are standing in; foreach targets
+	// the nested ones unless asked otherwise.
+	if _, ok := p["include-root"]; !ok {
+		repos = repos[1:]
+	}
+
+	if f, ok := p["filter"]; ok && len(f) > 0 {
+		if _, err := path.Match(f[0], ""); err != nil {
+			return newUsageError("invalid --filter pattern: " + f[0])
+		}
+
+		filtered := repos[:0]
+		for _, r := range repos {
+			byName, _ := path.Match(f[0], r.Metadata.Name)
+			byPath, _ := path.Match(f[0], r.RelPath)
+			if byName || byPath {
+				filtered = append(filtered, r)
+			}
+		}
+		repos = filtered
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No repositories matched.")
+		return nil
+	}
+
+	results := runForeach(repos, script, parallel)
+
+	// ------------------------------------------------------
+	// Summary
+	// ------------------------------------------------------
+	var failed []foreachResult
+	for _, r := range results {
+		if r.err != nil || r.exitCode != 0 {
+			failed = append(failed, r)
+		}
+	}
+
+	fmt.Println()
+	if len(failed) == 0 {
+		fmt.Printf("foreach: succeeded in %d repositories\n", len(results))
+		return nil
+	}
+
+	fmt.Printf("foreach: failed in %d of %d repositories:\n", len(failed), len(results))
+	for _, r := range failed {
+		if r.err != nil {
+			fmt.Printf("  %s (%s): %v\n", r.repo.Metadata.Name, r.repo.RelPath, r.err)
+			continue
+		}
+		fmt.Printf("  %s (%s): exit status %d\n", r.repo.Metadata.Name, r.repo.RelPath, r.exitCode)
+	}
+
+	// The children's own statuses are listed above; passing one on would
+	// make it look like one of mrvc's exit codes.
+	return &ExitError{
+		Code: ExitFailure,
+		Err:  fmt.Errorf("command failed in %d repositories", len(failed)),
+	}
+}
+
+// runForeach executes script in every repo using at most parallel
+// concurrent processes. Results are returned in repo order.
+func runForeach(repos []v1.RepoInfo, argv []string, parallel int) []foreachResult {
+	results := make([]foreachResult, len(repos))
+
+	var outMu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+
+	for i, repo := range repos {
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(i int, repo v1.RepoInfo) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			prefix := "[" + repo.Metadata.Name + "] "
+			stdout := &prefixWriter{out: os.Stdout, mu: &outMu, prefix: prefix}
+			stderr := &prefixWriter{out: os.Stderr, mu: &outMu, prefix: prefix}
+
+			cmd := scriptCommand(argv)
+			cmd.Dir = repo.Path
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			cmd.Env = append(os.Environ(),
+				"MRVC_REPO_NAME="+repo.Metadata.Name,
+				"MRVC_REPO_ID="+repo.Metadata.RepoID,
+				"MRVC_REPO_PATH="+filepath.FromSlash(repo.Path),
+				"MRVC_REPO_RELPATH="+repo.RelPath,
+			)
+
+			err := cmd.Run()
+			stdout.Flush()
+			stderr.Flush()
+
+			results[i] = foreachResult{repo: repo}
+
+			var exitErr *exec.ExitError
+			switch {
+			case err == nil:
+			case errors.As(err, &exitErr):
+				results[i].exitCode = exitErr.ExitCode()
+				if results[i].exitCode < 0 { // killed by a signal
+					results[i].exitCode = 1
+				}
+			default:
+				results[i].err = err
+				results[i].exitCode = 1
+			}
+		}(i, repo)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// scriptCommand runs a single argument through the platform shell so
+// pipes, redirects and $MRVC_* expansion work as users expect. Several
+// arguments are executed directly, keeping each one intact.
+func scriptCommand(argv []string) *exec.Cmd {
+	if len(argv) == 1 {
+		return shellCommand(argv[0])
+	}
+	return exec.Command(argv[0], argv[1:]...)
+}
+
+// shellCommand runs script through the platform shell.
+func shellCommand(script string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", script)
+	}
+	return exec.Command("sh", "-c", script)
+}
+
+// prefixWriter prefixes every output line and writes whole lines only,
+// so output of repos running in parallel never interleaves mid-line.
+type prefixWriter struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i+1])
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes a trailing line that did not end in a newline.
+func (w *prefixWriter) Flush() {
+	if len(w.buf) == 0 {
+		return
+	}
+	w.writeLine(append(w.buf, '\n'))
+	w.buf = nil
+}
+
+func (w *prefixWriter) writeLine(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, _ = io.WriteString(w.out, w.prefix)
+	_, _ = w.out.Write(line)
+}
+
+func init() {
+	Global.Register(&ForeachCommand{})
+}
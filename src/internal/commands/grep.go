@@ -0,0 +1,165 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/arg"
+	"MultiRepoVC/src/internal/utils/fs"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type GrepCommand struct {
+	BaseCommand
+}
+
+func (c *GrepCommand) Name() string { return "grep" }
+func (c *GrepCommand) Description() string {
+	return "Searches tracked files for a regular expression: grep [-i] [-n] [-l] [-c] [--recursive] <regex> [<rev>] [-- paths]. " +
+		"Without <rev> the working copies of tracked files are searched."
+}
+
+func (c *GrepCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "ignore-case", Short: "i", Type: ArgBool, Description: "match case-insensitively"},
+		{Name: "line-number", Short: "n", Type: ArgBool, Description: "prefix matches with their line number"},
+		{Name: "files-with-matches", Short: "l", Type: ArgBool, Description: "print only the names of matching files"},
+		{Name: "count", Short: "c", Type: ArgBool, Description: "print the number of matches per file"},
+		{Name: "recursive", Type: ArgBool, Description: "search nested repos as well"},
+	}
+}
+
+// grepOutput selects how matches are printed.
+type grepOutput struct {
+	lineNumbers bool
+	filesOnly   bool
+	count       bool
+	prefix      string // "<rev>:" when searching a revision
+}
+
+func (c *GrepCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+	if len(positional) == 0 || len(positional) > 2 {
+		return newUsageError("usage: mrvc grep [-i] [-n] [-l] [-c] <regex> [<rev>] [-- paths]")
+	}
+
+	pattern := positional[0]
+	if _, ok := p["ignore-case"]; ok {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return newUsageError("invalid regex: " + err.Error())
+	}
+
+	_, lineNumbers := p["line-number"]
+	_, filesOnly := p["files-with-matches"]
+	_, count := p["count"]
+	if filesOnly && count {
+		return newUsageError("-l and -c are mutually exclusive")
+	}
+	out := grepOutput{lineNumbers: lineNumbers, filesOnly: filesOnly, count: count}
+
+	paths := p[arg.Rest]
+
+	if _, ok := p["recursive"]; ok {
+		if len(positional) == 2 {
+			return newUsageError("grep --recursive searches working directories and takes no revision")
+		}
+		return c.recursive(re, paths, out)
+	}
+
+	vc := v1.New()
+
+	hash := ""
+	if len(positional) == 2 {
+		if hash, err = vc.ResolveRevision(positional[1]); err != nil {
+			return err
+		}
+		out.prefix = positional[1] + ":"
+	}
+
+	files, err := vc.Grep(re, hash, paths)
+	if err != nil {
+		return err
+	}
+
+	printGrep(files, "", out)
+	return nil
+}
+
+// recursive searches the current repo and every nested repo, printing
+// paths relative to the current repo.
+func (c *GrepCommand) recursive(re *regexp.Regexp, paths []string, out grepOutput) error {
+	repos, err := v1.DiscoverRepos(fs.GetCurrentDir())
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		specs, searched := nestedPathspecs(repo.RelPath, paths)
+		if !searched {
+			continue
+		}
+
+		files, err := v1.NewAt(repo.Path).Grep(re, "", specs)
+		if err != nil {
+			return fmt.Errorf("grep in %s failed: %w", repo.RelPath, err)
+		}
+
+		dir := ""
+		if repo.RelPath != "." {
+			dir = repo.RelPath + "/"
+		}
+		printGrep(files, dir, out)
+	}
+	return nil
+}
+
+// nestedPathspecs rewrites pathspecs relative to the current repo into
+// pathspecs relative to the nested repo at relPath. searched is false
+// when none of them reaches into that repo.
+func nestedPathspecs(relPath string, paths []string) (specs []string, searched bool) {
+	if relPath == "." || len(paths) == 0 {
+		return paths, true
+	}
+
+	for _, p := range paths {
+		spec := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+		switch {
+		case spec == "." || spec == relPath || strings.HasPrefix(relPath, spec+"/"):
+			specs = append(specs, ".")
+		case strings.HasPrefix(spec, relPath+"/"):
+			specs = append(specs, strings.TrimPrefix(spec, relPath+"/"))
+		}
+	}
+	return specs, len(specs) > 0
+}
+
+func printGrep(files []v1.GrepFile, dir string, out grepOutput) {
+	for _, f := range files {
+		name := out.prefix + dir + f.Path
+
+		switch {
+		case out.filesOnly:
+			fmt.Println(name)
+		case out.count:
+			fmt.Printf("%s:%d\n", name, len(f.Matches))
+		case f.Binary:
+			fmt.Printf("Binary file %s matches\n", name)
+		default:
+			for _, m := range f.Matches {
+				if out.lineNumbers {
+					fmt.Printf("%s:%d:%s\n", name, m.Line, m.Text)
+				} else {
+					fmt.Printf("%s:%s\n", name, m.Text)
+				}
+			}
+		}
+	}
+}
+
+func init() {
+	Global.Register(&GrepCommand{})
+}
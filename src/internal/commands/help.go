@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type HelpCommand struct {
+	BaseCommand
+}
+
+func (c *HelpCommand) Name() string { return "help" }
+func (c *HelpCommand) Description() string {
+	return "Lists the available commands, or describes one: help [<command>]. " +
+		"mrvc <command> --help does the same."
+}
+
+func (c *HelpCommand) Args() []ArgSpec { return nil }
+
+func (c *HelpCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+
+	switch len(positional) {
+	case 0:
+		Global.List()
+		return nil
+	case 1:
+	default:
+		return newUsageError("usage: mrvc help [<command>]")
+	}
+
+	name := positional[0]
+	cmd, _, err := Global.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if expansion, ok := Global.aliases[name]; ok && cmd.Name() != name {
+		fmt.Printf("'%s' is an alias for '%s'\n\n", name, expansion)
+	}
+	printHelp(cmd)
+	return nil
+}
+
+// printHelp prints the usage, description and flags of cmd.
+func printHelp(cmd Command) {
+	usage := "mrvc " + cmd.Name()
+	for _, s := range cmd.Args() {
+		if s.Required {
+			usage += " " + flagName(s.Name) + " <" + string(s.Type) + ">"
+		}
+	}
+	if len(cmd.Args()) > 0 {
+		usage += " [options]"
+	}
+
+	fmt.Println("usage:", usage)
+	fmt.Println()
+	fmt.Println(cmd.Description())
+
+	if len(cmd.Args()) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Options:")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, s := range cmd.Args() {
+		flag := "    " + flagName(s.Name)
+		switch {
+		case len(s.Name) == 1:
+			flag = flagName(s.Name)
+		case s.Short != "":
+			flag = flagName(s.Short) + ", " + flagName(s.Name)
+		}
+		if s.Type != ArgBool {
+			flag += " <" + string(s.Type) + ">"
+		}
+
+		var notes []string
+		if s.Required {
+			notes = append(notes, "required")
+		}
+		if s.Default != "" {
+			notes = append(notes, "default: "+s.Default)
+		}
+		if s.Repeatable {
+			notes = append(notes, "repeatable")
+		}
+
+		desc := s.Description
+		if len(notes) > 0 {
+			desc += " (" + strings.Join(notes, ", ") + ")"
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", flag, desc)
+	}
+	w.Flush()
+}
+
+// summary returns the first sentence of a command description, cut
+// before a colon introducing its synopsis.
+func summary(description string) string {
+	if i := strings.Index(description, ". "); i >= 0 {
+		description = description[:i]
+	}
+	if i := strings.Index(description, ": "); i >= 0 {
+		description = description[:i]
+	}
+	return strings.TrimSuffix(description, ".")
+}
+
+// suggest returns the candidate closest to name, or "" when none is
+// close: at most two edits away (and fewer than name has letters), or
+// starting with name.
+func suggest(name string, candidates []string) string {
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		d := editDistance(name, c)
+		if len(name) > 1 && strings.HasPrefix(c, name) {
+			d = min(d, 2)
+		}
+		if d < bestDist && d < len(name) {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// sortedNames returns the keys of m in order.
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Global.Register(&HelpCommand{})
+}
@@ -12,8 +12,12 @@ func (c *InitCommand) Name() string { return "init" }
 func (c *InitCommand) Description() string {
 	return "Initializes a new MRVC repository."
 }
-func (c *InitCommand) RequiredArgs() []string { return []string{"name", "author"} }
-func (c *InitCommand) OptionalArgs() []string { return []string{} }
+func (c *InitCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "name", Type: ArgString, Required: true, Description: "repository name"},
+		{Name: "author", Type: ArgString, Required: true, Description: "repository author"},
+	}
+}
 
 func (c *InitCommand) ExecuteCommand(p map[string][]string) error {
 	name := p["name"][0]
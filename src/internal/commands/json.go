@@ -0,0 +1,191 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONSchemaVersion is stamped on every JSON document. It only changes
+// for incompatible changes (removed or retyped fields); new fields may
+// appear without a bump. The schema is documented in docs/v1/JSON.md.
+const JSONSchemaVersion = 1
+
+type statusJSON struct {
+	SchemaVersion int              `json:"schema_version"`
+	Head          *string          `json:"head"`
+	Clean         bool             `json:"clean"`
+	Modified      []string         `json:"modified"`
+	Deleted       []string         `json:"deleted"`
+	Untracked     []string         `json:"untracked"`
+	Renamed       []renameJSON     `json:"renamed"`
+	NestedRepos   []nestedRepoJSON `json:"nested_repos"`
+	Warnings      []string         `json:"warnings"`
+}
+
+type renameJSON struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Similarity int    `json:"similarity"`
+	Copy       bool   `json:"copy"`
+}
+
+type nestedRepoJSON struct {
+	Kind    string `json:"kind"`
+	RepoID  string `json:"repo_id"`
+	Name    string `json:"name"`
+	OldName string `json:"old_name"`
+	Path    string `json:"path"`
+	OldPath string `json:"old_path"`
+}
+
+type commitJSON struct {
+	Hash           string       `json:"hash"`
+	Tree           string       `json:"tree"`
+	Parent         *string      `json:"parent"`
+	Author         string       `json:"author"` // author name
+	AuthorIdentity identityJSON `json:"author_identity"`
+	Committer      identityJSON `json:"committer"`
+	Timestamp      int64        `json:"timestamp"`
+	Message        string       `json:"message"`
+	Repo           string       `json:"repo,omitempty"`
+	Rename         *renameJSON  `json:"rename,omitempty"`
+}
+
+type identityJSON struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Timestamp int64  `json:"timestamp"`
+	TZOffset  int    `json:"tz_offset"`
+}
+
+type logJSON struct {
+	SchemaVersion int          `json:"schema_version"`
+	Commits       []commitJSON `json:"commits"`
+}
+
+type fileChangeJSON struct {
+	Status     string `json:"status"`
+	Path       string `json:"path"`
+	From       string `json:"from,omitempty"`
+	Similarity int    `json:"similarity,omitempty"`
+}
+
+type showJSON struct {
+	SchemaVersion int              `json:"schema_version"`
+	Commit        commitJSON       `json:"commit"`
+	Files         []fileChangeJSON `json:"files"`
+	NestedRepos   []nestedRepoJSON `json:"nested_repos"`
+}
+
+func newStatusJSON(s v1.StatusResult) statusJSON {
+	out := statusJSON{
+		SchemaVersion: JSONSchemaVersion,
+		Head:          optional(s.Head),
+		Clean:         s.Head != "" && s.Clean(),
+		Modified:      nonNil(s.Modified),
+		Deleted:       nonNil(s.Deleted),
+		Untracked:     nonNil(s.Untracked),
+		Renamed:       []renameJSON{},
+		NestedRepos:   newNestedReposJSON(s.NestedRepos),
+		Warnings:      nonNil(s.Warnings),
+	}
+	for _, r := range s.Renamed {
+		out.Renamed = append(out.Renamed, newRenameJSON(r))
+	}
+	return out
+}
+
+func newRenameJSON(r v1.Rename) renameJSON {
+	return renameJSON{From: r.From, To: r.To, Similarity: r.Similarity, Copy: r.Copy}
+}
+
+func newNestedReposJSON(changes []v1.NestedRepoChange) []nestedRepoJSON {
+	out := []nestedRepoJSON{}
+	for _, c := range changes {
+		out = append(out, nestedRepoJSON{
+			Kind:    c.Kind,
+			RepoID:  c.RepoID,
+			Name:    c.Name,
+			OldName: c.OldName,
+			Path:    c.Path,
+			OldPath: c.OldPath,
+		})
+	}
+	return out
+}
+
+func newCommitJSON(e v1.LogEntry) commitJSON {
+	out := commitJSON{
+		Hash:           e.Hash,
+		Tree:           e.Commit.Tree,
+		Parent:         optional(e.Commit.Parent),
+		Author:         e.Commit.Author.Name,
+		AuthorIdentity: newIdentityJSON(e.Commit.Author),
+		Committer:      newIdentityJSON(e.Commit.Committer),
+		Timestamp:      e.Time(),
+		Message:        e.Commit.Message,
+		Repo:           e.Repo,
+	}
+	if e.Rename != nil {
+		r := newRenameJSON(*e.Rename)
+		out.Rename = &r
+	}
+	return out
+}
+
+func newIdentityJSON(id model.Identity) identityJSON {
+	return identityJSON{Name: id.Name, Email: id.Email, Timestamp: id.Time().UnixMilli(), TZOffset: id.TZOffset}
+}
+
+func newLogJSON(entries []v1.LogEntry) logJSON {
+	out := logJSON{SchemaVersion: JSONSchemaVersion, Commits: []commitJSON{}}
+	for _, e := range entries {
+		out.Commits = append(out.Commits, newCommitJSON(e))
+	}
+	return out
+}
+
+func newShowJSON(e v1.LogEntry, d v1.DiffResult) showJSON {
+	out := showJSON{
+		SchemaVersion: JSONSchemaVersion,
+		Commit:        newCommitJSON(e),
+		Files:         []fileChangeJSON{},
+		NestedRepos:   newNestedReposJSON(d.NestedRepos),
+	}
+	for _, f := range d.Files {
+		change := fileChangeJSON{Status: f.Status, Path: f.Path}
+		if f.Rename != nil {
+			change.From, change.Similarity = f.Rename.From, f.Rename.Similarity
+		}
+		out.Files = append(out.Files, change)
+	}
+	return out
+}
+
+// printJSON writes v as indented JSON to stdout.
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// optional maps "" to JSON null.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nonNil makes empty lists encode as [] instead of null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
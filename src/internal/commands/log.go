@@ -0,0 +1,326 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/utils/fs"
+	"MultiRepoVC/src/internal/utils/time"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type LogCommand struct {
+	BaseCommand
+}
+
+func (c *LogCommand) Name() string { return "log" }
+func (c *LogCommand) Description() string {
+	return "Shows commit history, newest first. --recursive interleaves all nested repos; " +
+		"--repo <name> includes and --repo '!<name>' excludes repos; " +
+		"--follow <path> lists the commits changing a file across renames; " +
+		"--format oneline|short|full|json or a text/template over the commit; " +
+		"--all lists the history of every ref, --graph draws it as lanes."
+}
+
+func (c *LogCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "author", Type: ArgString, Description: "only commits whose author contains this"},
+		{Name: "grep", Type: ArgString, Description: "only commits whose message contains this"},
+		{Name: "since", Type: ArgString, Description: "only commits at or after this date"},
+		{Name: "until", Type: ArgString, Description: "only commits at or before this date (a date alone includes that whole day)"},
+		{Name: "max-count", Short: "n", Type: ArgInt, Description: "show at most this many commits"},
+		{Name: "recursive", Type: ArgBool, Description: "interleave the histories of all nested repos"},
+		{Name: "repo", Type: ArgString, Repeatable: true, Description: "with --recursive: include <name>, or exclude '!<name>'"},
+		{Name: "follow", Type: ArgString, Description: "only commits changing this path, across renames"},
+		{Name: "format", Type: ArgString, Description: "oneline, short, full, json or a text/template"},
+		{Name: "all", Type: ArgBool, Description: "show the history of every ref"},
+		{Name: "graph", Type: ArgBool, Description: "draw the history as lanes"},
+	}
+}
+
+func (c *LogCommand) ExecuteCommand(p map[string][]string) error {
+	filter, err := logFilter(p)
+	if err != nil {
+		return err
+	}
+
+	format, positional, err := logFormat(p)
+	if err != nil {
+		return err
+	}
+
+	follow, following := p["follow"]
+
+	_, all := p["all"]
+	_, graph := p["graph"]
+
+	if _, ok := p["recursive"]; ok {
+		if all || graph {
+			return newUsageError("--all and --graph cannot be combined with --recursive")
+		}
+		if len(positional) > 0 {
+			return newUsageError("log --recursive always starts at each repo's HEAD")
+		}
+		if following {
+			return newUsageError("--follow cannot be combined with --recursive")
+		}
+		return c.recursive(filter, p["repo"], format)
+	}
+
+	if _, ok := p["repo"]; ok {
+		return newUsageError("--repo requires --recursive")
+	}
+
+	vc := v1.New()
+
+	if all || graph {
+		if following {
+			return newUsageError("--follow cannot be combined with --all or --graph")
+		}
+		return c.topo(vc, positional, filter, format, all, graph)
+	}
+
+	start := "HEAD"
+	if len(positional) > 0 {
+		start = positional[0]
+	}
+
+	hash, err := vc.ResolveRevision(start)
+	if err != nil {
+		return err
+	}
+
+	var entries []v1.LogEntry
+	if following {
+		entries, err = vc.FollowLog(hash, follow[0], filter)
+	} else {
+		entries, err = vc.Log(hash, filter)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeLog(entries, format, nil)
+}
+
+// topo prints the history of every ref (--all) or of one revision in
+// topological order, decorated with ref names and optionally as a graph.
+func (c *LogCommand) topo(vc *v1.VersionControlV1, positional []string, filter v1.LogFilter, format string, all, graph bool) error {
+	if graph {
+		if format == "json" {
+			return newUsageError("--graph cannot be combined with --format json")
+		}
+		// a filtered-out commit would leave a hole in its lane
+		if filter.Author != "" || filter.Grep != "" || filter.Since != 0 || filter.Until != 0 {
+			return newUsageError("--graph cannot be combined with --author, --grep, --since or --until")
+		}
+	}
+
+	refs, err := vc.Refs()
+	if err != nil {
+		return err
+	}
+
+	var starts []string
+	if all {
+		if len(positional) > 0 {
+			return newUsageError("log --all takes no revision")
+		}
+		for _, r := range refs {
+			starts = append(starts, r.Hash)
+		}
+		if len(starts) == 0 {
+			return errors.New("no commits yet")
+		}
+	} else {
+		start := "HEAD"
+		if len(positional) > 0 {
+			start = positional[0]
+		}
+		hash, err := vc.ResolveRevision(start)
+		if err != nil {
+			return err
+		}
+		starts = []string{hash}
+	}
+
+	entries, err := vc.TopoLog(starts, filter)
+	if err != nil {
+		return err
+	}
+
+	decorations := make(map[string][]string)
+	for _, r := range refs {
+		decorations[r.Hash] = append(decorations[r.Hash], r.Decoration())
+	}
+
+	if !graph {
+		return writeLog(entries, format, decorations)
+	}
+
+	if format == "" {
+		format = "oneline"
+	}
+	tmpl, err := logTemplate(format)
+	if err != nil {
+		return err
+	}
+	return printLogGraph(entries, tmpl, decorations)
+}
+
+// recursive prints the merged history of the current repo and every
+// nested repo, restricted by --repo selectors.
+func (c *LogCommand) recursive(filter v1.LogFilter, selectors []string, format string) error {
+	repos, err := v1.DiscoverRepos(fs.GetCurrentDir())
+	if err != nil {
+		return err
+	}
+
+	repos, err = selectRepos(repos, selectors)
+	if err != nil {
+		return err
+	}
+
+	entries, err := v1.RecursiveLog(repos, filter)
+	if err != nil {
+		return err
+	}
+
+	return writeLog(entries, format, nil)
+}
+
+// selectRepos applies --repo selectors: plain names (or repo_ids) keep
+// only those repos, names prefixed with "!" drop them.
+func selectRepos(repos []v1.RepoInfo, selectors []string) ([]v1.RepoInfo, error) {
+	include := make(map[string]bool)
+	exclude := make(map[string]bool)
+
+	for _, s := range selectors {
+		excluded := strings.HasPrefix(s, "!")
+
+		repo, err := v1.FindRepo(repos, strings.TrimPrefix(s, "!"))
+		if err != nil {
+			return nil, err
+		}
+
+		if excluded {
+			exclude[repo.Path] = true
+		} else {
+			include[repo.Path] = true
+		}
+	}
+
+	selected := make([]v1.RepoInfo, 0, len(repos))
+	for _, r := range repos {
+		if exclude[r.Path] || (len(include) > 0 && !include[r.Path]) {
+			continue
+		}
+		selected = append(selected, r)
+	}
+	return selected, nil
+}
+
+// logFilter builds a LogFilter from --author, --grep, --since, --until
+// and --max-count.
+func logFilter(p map[string][]string) (v1.LogFilter, error) {
+	var filter v1.LogFilter
+
+	if a, ok := p["author"]; ok && len(a) > 0 {
+		filter.Author = a[0]
+	}
+	if g, ok := p["grep"]; ok && len(g) > 0 {
+		filter.Grep = g[0]
+	}
+
+	if s, ok := p["since"]; ok && len(s) > 0 {
+		ms, err := time.ParseDate(s[0])
+		if err != nil {
+			return filter, newUsageError("invalid --since date: " + s[0])
+		}
+		filter.Since = ms
+	}
+
+	if u, ok := p["until"]; ok && len(u) > 0 {
+		ms, err := time.ParseDateUntil(u[0])
+		if err != nil {
+			return filter, newUsageError("invalid --until date: " + u[0])
+		}
+		filter.Until = ms
+	}
+
+	if n, ok := p["max-count"]; ok && len(n) > 0 {
+		count, err := strconv.Atoi(n[0])
+		if err != nil || count < 1 {
+			return filter, newUsageError("--max-count must be a positive number")
+		}
+		filter.MaxCount = count
+	}
+
+	return filter, nil
+}
+
+// logFormat returns the --format of log and the positional arguments.
+func logFormat(p map[string][]string) (string, []string, error) {
+	positional := p["positional"]
+
+	f, ok := p["format"]
+	if !ok {
+		return "", positional, nil
+	}
+
+	if f[0] != "json" {
+		// reject a broken template before walking any history
+		if _, err := logTemplate(f[0]); err != nil {
+			return "", nil, err
+		}
+	}
+	return f[0], positional, nil
+}
+
+// writeLog prints entries in the given --format: "" for the default
+// human output, "json", a named format or a template. refs holds the
+// ref names to show next to commits, keyed by hash (nil for none).
+func writeLog(entries []v1.LogEntry, format string, refs map[string][]string) error {
+	switch format {
+	case "":
+		printLog(entries, refs)
+		return nil
+	case "json":
+		return printJSON(newLogJSON(entries))
+	}
+
+	tmpl, err := logTemplate(format)
+	if err != nil {
+		return err
+	}
+	return printLogTemplate(entries, tmpl, refs)
+}
+
+func printLog(entries []v1.LogEntry, refs map[string][]string) {
+	for _, e := range entries {
+		fmt.Printf("commit %s", e.Hash)
+		if e.Repo != "" {
+			fmt.Printf(" [%s]", e.Repo)
+		}
+		if names := refs[e.Hash]; len(names) > 0 {
+			fmt.Printf(" (%s)", strings.Join(names, ", "))
+		}
+		fmt.Println()
+		fmt.Printf("Author: %s\n", e.Commit.Author)
+		fmt.Printf("Date:   %s\n", time.FormatISOZone(e.Commit.Author.Time().UnixMilli(), e.Commit.Author.TZOffset))
+		fmt.Println()
+		for _, line := range strings.Split(e.Commit.Message, "\n") {
+			fmt.Println("    " + line)
+		}
+		if e.Rename != nil {
+			fmt.Println()
+			fmt.Println("    " + e.Rename.String())
+		}
+		fmt.Println()
+	}
+}
+
+func init() {
+	Global.Register(&LogCommand{})
+}
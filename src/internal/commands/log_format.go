@@ -0,0 +1,125 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// logCommit is what a `log --format` template is executed against, once
+// per commit. The output of every execution is followed by a newline.
+type logCommit struct {
+	Hash        string
+	ShortHash   string
+	Tree        string
+	Parents     []string // empty for a root commit
+	Author      string   // "Name <email>"
+	AuthorName  string
+	AuthorEmail string
+	Committer   string    // "Name <email>"
+	Timestamp   time.Time // author date, in the author's time zone
+	Message     string
+	Subject     string     // first line of Message
+	Body        string     // Message without the subject
+	Repo        string     // log --recursive only
+	Rename      *v1.Rename // log --follow only
+	Refs        []string   // log --all and --graph only, e.g. "HEAD", "stash"
+}
+
+func newLogCommit(e v1.LogEntry, refs map[string][]string) logCommit {
+	c := logCommit{
+		Hash:        e.Hash,
+		ShortHash:   v1.ShortHash(e.Hash),
+		Tree:        e.Commit.Tree,
+		Parents:     []string{},
+		Author:      e.Commit.Author.String(),
+		AuthorName:  e.Commit.Author.Name,
+		AuthorEmail: e.Commit.Author.Email,
+		Committer:   e.Commit.Committer.String(),
+		Timestamp:   e.Commit.Author.Time(),
+		Message:     e.Commit.Message,
+		Subject:     e.Subject(),
+		Body:        e.Body(),
+		Repo:        e.Repo,
+		Rename:      e.Rename,
+		Refs:        refs[e.Hash],
+	}
+	if e.Commit.Parent != "" {
+		c.Parents = append(c.Parents, e.Commit.Parent)
+	}
+	return c
+}
+
+// logFormats are the named formats of `log --format`, besides "json".
+var logFormats = map[string]string{
+	"oneline": `{{.ShortHash}}{{with .Repo}} [{{.}}]{{end}}{{with .Refs}} ({{join . ", "}}){{end}} {{.Subject}}`,
+
+	"short": `commit {{.Hash}}{{with .Repo}} [{{.}}]{{end}}{{with .Refs}} ({{join . ", "}}){{end}}
+Author: {{.Author}}
+
+{{indent .Subject}}
+`,
+
+	"full": `commit {{.Hash}}{{with .Repo}} [{{.}}]{{end}}{{with .Refs}} ({{join . ", "}}){{end}}
+Tree:   {{.Tree}}
+{{range .Parents}}Parent: {{.}}
+{{end}}Author: {{.Author}}
+Commit: {{.Committer}}
+Date:   {{iso .Timestamp}}
+
+{{indent .Message}}
+{{with .Rename}}
+{{indent .String}}
+{{end}}`,
+}
+
+// logTemplateFuncs are available to every --format template.
+var logTemplateFuncs = template.FuncMap{
+	// indent prefixes every line of s with four spaces.
+	"indent": func(s string) string {
+		return "    " + strings.ReplaceAll(s, "\n", "\n    ")
+	},
+	// iso formats t as 2025-11-21T18:22:11Z or 2025-11-21T20:22:11+02:00.
+	"iso": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"join": strings.Join,
+}
+
+// logTemplate returns the template of a named format or parses format
+// as a template of its own.
+func logTemplate(format string) (*template.Template, error) {
+	text, named := logFormats[format]
+	if !named {
+		if !strings.Contains(format, "{{") {
+			return nil, newUsageError("unknown --format: " + format + " (use oneline, short, full, json or a template)")
+		}
+		text = format
+	}
+
+	tmpl, err := template.New("format").Funcs(logTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, &UsageError{Err: fmt.Errorf("invalid --format template: %w", err)}
+	}
+	return tmpl, nil
+}
+
+// printLogTemplate executes tmpl for every entry.
+func printLogTemplate(entries []v1.LogEntry, tmpl *template.Template, refs map[string][]string) error {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	for _, e := range entries {
+		if err := tmpl.Execute(out, newLogCommit(e, refs)); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+		if err := out.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// logGraph draws `log --graph` lanes, two columns each. A lane holds the
+// commit expected next on that line of history; "*" marks the commit of
+// a row, "|" a lane passing by, and "/" a lane joining the one on its
+// left once both reach the same commit.
+type logGraph struct {
+	lanes []string // "" marks a lane whose history ended
+}
+
+// commit places hash in its lane (opening a new one for a tip) and
+// returns the commit row prefix and the prefix of further text lines.
+func (g *logGraph) commit(hash, parent string) (string, string) {
+	col := g.lane(hash)
+	if col < 0 {
+		g.lanes = append(g.lanes, hash)
+		col = len(g.lanes) - 1
+	}
+
+	row := g.row(func(i int) byte {
+		if i == col {
+			return '*'
+		}
+		return '|'
+	})
+
+	g.lanes[col] = parent
+	return row, g.row(func(i int) byte {
+		if g.lanes[i] == "" {
+			return ' '
+		}
+		return '|'
+	})
+}
+
+// collapse joins lanes waiting for the same commit and closes ended
+// lanes, returning one connecting row per join.
+func (g *logGraph) collapse() []string {
+	var rows []string
+
+	for {
+		from, into := g.joinable()
+		if from < 0 {
+			return rows
+		}
+
+		if from < len(g.lanes)-1 || into >= 0 {
+			rows = append(rows, g.joinRow(from, into))
+		}
+		g.lanes = append(g.lanes[:from], g.lanes[from+1:]...)
+	}
+}
+
+// joinable returns a lane to remove and the lane it merges into (-1 for
+// an ended lane), or -1 when every lane is distinct and live.
+func (g *logGraph) joinable() (int, int) {
+	for j := len(g.lanes) - 1; j >= 0; j-- {
+		if g.lanes[j] == "" {
+			return j, -1
+		}
+		for k := 0; k < j; k++ {
+			if g.lanes[k] == g.lanes[j] {
+				return j, k
+			}
+		}
+	}
+	return -1, -1
+}
+
+// joinRow draws lane from moving left into lane into ("|/", "|_|/"),
+// shifting the lanes right of it one lane left.
+func (g *logGraph) joinRow(from, into int) string {
+	row := []byte(strings.Repeat(" ", 2*len(g.lanes)-1))
+
+	for i := range g.lanes {
+		switch {
+		case i < from && g.lanes[i] != "":
+			row[2*i] = '|'
+		case i == from && into >= 0:
+			row[2*i-1] = '/'
+			for p := 2*into + 1; p < 2*i-1; p += 2 {
+				row[p] = '_'
+			}
+		case i > from:
+			row[2*i-1] = '/'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+func (g *logGraph) lane(hash string) int {
+	for i, h := range g.lanes {
+		if h == hash {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *logGraph) row(cell func(int) byte) string {
+	var b strings.Builder
+	for i := range g.lanes {
+		b.WriteByte(cell(i))
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// printLogGraph prints entries (in topological order) next to their
+// lanes, each rendered with tmpl.
+func printLogGraph(entries []v1.LogEntry, tmpl *template.Template, refs map[string][]string) error {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	var g logGraph
+	for _, e := range entries {
+		var text bytes.Buffer
+		if err := tmpl.Execute(&text, newLogCommit(e, refs)); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+
+		first, next := g.commit(e.Hash, e.Commit.Parent)
+		for i, line := range strings.Split(text.String(), "\n") {
+			prefix := next
+			if i == 0 {
+				prefix = first
+			}
+			fmt.Fprintln(out, strings.TrimRight(prefix+line, " "))
+		}
+
+		for _, row := range g.collapse() {
+			fmt.Fprintln(out, row)
+		}
+	}
+	return nil
+}
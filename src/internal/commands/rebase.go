@@ -0,0 +1,105 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+)
+
+type RebaseCommand struct {
+	BaseCommand
+}
+
+func (c *RebaseCommand) Name() string { return "rebase" }
+func (c *RebaseCommand) Description() string {
+	return "Replays the commits HEAD has on top of <upstream> onto it: rebase [--onto <newbase>] <upstream> [--autosquash]. " +
+		"--autosquash folds 'fixup! <subject>' commits into the commit they name. " +
+		"On conflicts use --continue, --skip or --abort."
+}
+
+func (c *RebaseCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "onto", Type: ArgString, Description: "replay onto this commit instead of <upstream>"},
+		{Name: "autosquash", Type: ArgBool, Description: "fold 'fixup! <subject>' commits into their target"},
+		{Name: "continue", Type: ArgBool, Description: "resume after resolving conflicts"},
+		{Name: "skip", Type: ArgBool, Description: "drop the conflicting commit and go on"},
+		{Name: "abort", Type: ArgBool, Description: "give up and restore the original HEAD"},
+	}
+}
+
+func (c *RebaseCommand) ExecuteCommand(p map[string][]string) error {
+	vc := v1.New()
+
+	positional := p["positional"]
+	onto, hasOnto := p["onto"]
+
+	action := ""
+	for _, flag := range []string{"continue", "skip", "abort"} {
+		if _, ok := p[flag]; ok {
+			if action != "" {
+				return newUsageError("--continue, --skip and --abort are mutually exclusive")
+			}
+			action = flag
+		}
+	}
+	if action != "" && (len(positional) > 0 || hasOnto) {
+		return newUsageError("--" + action + " takes no revisions")
+	}
+
+	before, _ := vc.ResolveRevision("HEAD")
+
+	var err error
+	switch action {
+	case "continue":
+		err = vc.RebaseContinue()
+	case "skip":
+		err = vc.RebaseSkip()
+	case "abort":
+		if err := vc.RebaseAbort(); err != nil {
+			return err
+		}
+		fmt.Println("Rebase aborted")
+		return nil
+	default:
+		_, autosquash := p["autosquash"]
+		err = c.start(vc, positional, onto, hasOnto, autosquash)
+	}
+	if err != nil {
+		return err
+	}
+
+	head, err := vc.ResolveRevision("HEAD")
+	if err != nil {
+		return err
+	}
+	if head == before && action == "" {
+		fmt.Println("Current HEAD is up to date.")
+		return nil
+	}
+	fmt.Printf("Successfully rebased, HEAD is now at %s\n", v1.ShortHash(head))
+	return nil
+}
+
+// start resolves the revisions of a new rebase and runs it.
+func (c *RebaseCommand) start(vc *v1.VersionControlV1, positional, onto []string, hasOnto, autosquash bool) error {
+	if len(positional) != 1 {
+		return newUsageError("rebase takes exactly one upstream revision")
+	}
+
+	upstream, err := vc.ResolveRevision(positional[0])
+	if err != nil {
+		return err
+	}
+
+	newBase := ""
+	if hasOnto {
+		if newBase, err = vc.ResolveRevision(onto[0]); err != nil {
+			return err
+		}
+	}
+
+	return vc.Rebase(upstream, newBase, autosquash)
+}
+
+func init() {
+	Global.Register(&RebaseCommand{})
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+)
+
+type ReflogCommand struct {
+	BaseCommand
+}
+
+func (c *ReflogCommand) Name() string { return "reflog" }
+func (c *ReflogCommand) Description() string {
+	return "Shows every recorded movement of a ref (default HEAD), newest first. Entries resolve as HEAD@{n}."
+}
+
+func (c *ReflogCommand) Args() []ArgSpec { return nil }
+func (c *ReflogCommand) ExecuteCommand(p map[string][]string) error {
+	ref := "HEAD"
+	if pos := p["positional"]; len(pos) > 0 {
+		ref = pos[0]
+	}
+
+	vc := v1.New()
+	entries, err := vc.Reflog(ref)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No reflog entries for " + ref + ".")
+		return nil
+	}
+
+	for i, e := range entries {
+		fmt.Printf("%s %s@{%d}: %s\n", v1.ShortHash(e.New), ref, i, e.Reason)
+	}
+	return nil
+}
+
+func init() {
+	Global.Register(&ReflogCommand{})
+}
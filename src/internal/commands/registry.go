@@ -1,19 +1,30 @@
 package commands
 
 import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
 )
 
 var Global = NewRegistry()
 
+// aliasPrefix marks alias definitions in the config, e.g.
+//
+//	alias.st = status --porcelain
+const aliasPrefix = "alias."
+
 type Registry struct {
 	commands map[string]Command
+	aliases  map[string]string // alias name → expansion
 }
 
 func NewRegistry() *Registry {
 	return &Registry{
 		commands: make(map[string]Command),
+		aliases:  make(map[string]string),
 	}
 }
 
@@ -21,22 +32,123 @@ func (r *Registry) Register(cmd Command) {
 	r.commands[cmd.Name()] = cmd
 }
 
-func (r *Registry) Get(name string) (Command, error) {
-	cmd, ok := r.commands[name]
-	if !ok {
-		return nil, fmt.Errorf("unknown command: %s", name)
+// LoadAliases registers every alias.<name> setting of config. Aliases
+// never shadow a command of the same name.
+func (r *Registry) LoadAliases(config *v1.Config) {
+	for _, e := range config.Entries() {
+		if len(e.Key) > len(aliasPrefix) && strings.EqualFold(e.Key[:len(aliasPrefix)], aliasPrefix) {
+			r.aliases[e.Key[len(aliasPrefix):]] = e.Value
+		}
+	}
+}
+
+// Get returns the command called name. An alias is expanded first; the
+// arguments of its expansion are returned to be put in front of the
+// user's own. Aliases may refer to other aliases.
+func (r *Registry) Get(name string) (Command, []string, error) {
+	var args []string
+	chain := []string{name}
+
+	for {
+		if cmd, ok := r.commands[name]; ok {
+			return cmd, args, nil
+		}
+
+		expansion, ok := r.aliases[name]
+		if !ok {
+			if len(chain) > 1 {
+				return nil, nil, &UsageError{Err: fmt.Errorf("alias %s expands to unknown command: %s", chain[0], name)}
+			}
+			msg := "unknown command: " + name
+			if s := suggest(name, append(sortedNames(r.commands), sortedNames(r.aliases)...)); s != "" {
+				msg += " (did you mean " + s + "?)"
+			}
+			return nil, nil, newUsageError(msg)
+		}
+
+		words, err := splitAlias(expansion)
+		if err != nil {
+			return nil, nil, &UsageError{Err: fmt.Errorf("alias %s: %w", name, err)}
+		}
+		if len(words) == 0 {
+			return nil, nil, &UsageError{Err: fmt.Errorf("alias %s is empty", name)}
+		}
+
+		name, args = words[0], append(words[1:], args...)
+
+		for _, seen := range chain {
+			if seen == name {
+				return nil, nil, &UsageError{Err: fmt.Errorf("alias loop: %s", strings.Join(append(chain, name), " → "))}
+			}
+		}
+		chain = append(chain, name)
+	}
+}
+
+// splitAlias splits an alias expansion into words at whitespace. Single
+// or double quotes keep whitespace inside a word.
+func splitAlias(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, c := range s {
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(c)
+		case c == '"' || c == '\'':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, word.String())
 	}
-	return cmd, nil
+	return words, nil
 }
 
+// List prints every command with a one-line summary, followed by the
+// aliases from the config.
 func (r *Registry) List() {
-	fmt.Println("\nAvailable commands:")
-	for name, cmd := range r.commands {
-		fmt.Printf("  %s  -  %s\n", name, cmd.Description())
-		fmt.Printf("      Required: --%s\n", strings.Join(cmd.RequiredArgs(), " --"))
-		if len(cmd.OptionalArgs()) > 0 {
-			fmt.Printf("      Optional: --%s\n", strings.Join(cmd.OptionalArgs(), " --"))
-		}
-		fmt.Println()
+	fmt.Println("usage: mrvc <command> [options]")
+	fmt.Println()
+	fmt.Println("Available commands:")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range sortedNames(r.commands) {
+		fmt.Fprintf(w, "  %s\t%s\n", name, summary(r.commands[name].Description()))
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("Use 'mrvc help <command>' or 'mrvc <command> --help' for its options.")
+
+	if len(r.aliases) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Aliases:")
+	for _, name := range sortedNames(r.aliases) {
+		if _, shadowed := r.commands[name]; shadowed {
+			fmt.Printf("  %s = %s  (ignored, %s is a command)\n", name, r.aliases[name], name)
+			continue
+		}
+		fmt.Printf("  %s = %s\n", name, r.aliases[name])
 	}
 }
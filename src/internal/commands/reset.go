@@ -0,0 +1,61 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+)
+
+type ResetCommand struct {
+	BaseCommand
+}
+
+func (c *ResetCommand) Name() string { return "reset" }
+func (c *ResetCommand) Description() string {
+	return "Moves HEAD to a revision: reset [--soft|--mixed|--hard] <rev>. " +
+		"--hard also rewrites tracked files; nested repos are left untouched."
+}
+
+func (c *ResetCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "soft", Type: ArgBool, Description: "move HEAD only"},
+		{Name: "mixed", Type: ArgBool, Description: "move HEAD and reset the index (default)"},
+		{Name: "hard", Type: ArgBool, Description: "also rewrite tracked files"},
+	}
+}
+
+func (c *ResetCommand) ExecuteCommand(p map[string][]string) error {
+	mode := v1.ResetMixed
+	modes := 0
+	for flag, m := range map[string]v1.ResetMode{"soft": v1.ResetSoft, "mixed": v1.ResetMixed, "hard": v1.ResetHard} {
+		if _, ok := p[flag]; ok {
+			mode = m
+			modes++
+		}
+	}
+	if modes > 1 {
+		return newUsageError("--soft, --mixed and --hard are mutually exclusive")
+	}
+
+	positional := p["positional"]
+	if len(positional) != 1 {
+		return newUsageError("reset takes exactly one revision")
+	}
+	rev := positional[0]
+
+	vc := v1.New()
+	target, err := vc.ResolveRevision(rev)
+	if err != nil {
+		return err
+	}
+
+	if err := vc.Reset(target, rev, mode); err != nil {
+		return err
+	}
+
+	fmt.Printf("HEAD is now at %s (%s reset)\n", v1.ShortHash(target), mode)
+	return nil
+}
+
+func init() {
+	Global.Register(&ResetCommand{})
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"fmt"
+)
+
+type RevertCommand struct {
+	BaseCommand
+}
+
+func (c *RevertCommand) Name() string { return "revert" }
+func (c *RevertCommand) Description() string {
+	return "Creates a commit undoing a revision: revert <rev> [--author a]. " +
+		"After resolving conflicts run revert --continue, or revert --abort."
+}
+
+func (c *RevertCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "author", Type: ArgString, Description: "author of the revert commit"},
+		{Name: "continue", Type: ArgBool, Description: "commit after resolving conflicts"},
+		{Name: "abort", Type: ArgBool, Description: "give up and restore the original HEAD"},
+	}
+}
+
+func (c *RevertCommand) ExecuteCommand(p map[string][]string) error {
+	vc := v1.New()
+
+	var author model.Identity
+	if a, ok := p["author"]; ok && len(a) > 0 {
+		author = v1.ParseIdentity(a[0])
+	}
+
+	_, cont := p["continue"]
+	_, abort := p["abort"]
+	positional := p["positional"]
+
+	switch {
+	case cont && abort:
+		return newUsageError("--continue and --abort are mutually exclusive")
+	case (cont || abort) && len(positional) > 0:
+		return newUsageError("--continue and --abort take no revision")
+	case abort:
+		if err := vc.RevertAbort(); err != nil {
+			return err
+		}
+		fmt.Println("Revert aborted")
+		return nil
+	case cont:
+		if err := vc.RevertContinue(author); err != nil {
+			return err
+		}
+	default:
+		if len(positional) != 1 {
+			return newUsageError("revert takes exactly one revision")
+		}
+
+		hash, err := vc.ResolveRevision(positional[0])
+		if err != nil {
+			return err
+		}
+		if err := vc.Revert(hash, author); err != nil {
+			return err
+		}
+	}
+
+	head, err := vc.ResolveRevision("HEAD")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("HEAD is now at %s\n", v1.ShortHash(head))
+	return nil
+}
+
+func init() {
+	Global.Register(&RevertCommand{})
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+)
+
+type ShowCommand struct {
+	BaseCommand
+}
+
+func (c *ShowCommand) Name() string { return "show" }
+func (c *ShowCommand) Description() string {
+	return "Shows a commit and the files and nested repos it changed: show [<rev>] [--format json]."
+}
+
+func (c *ShowCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "format", Type: ArgString, Description: "json for machine-readable output"},
+	}
+}
+
+func (c *ShowCommand) ExecuteCommand(p map[string][]string) error {
+	format, err := outputFormat(p, "json")
+	if err != nil {
+		return err
+	}
+
+	positional := p["positional"]
+	if len(positional) > 1 {
+		return newUsageError("show takes at most one revision")
+	}
+
+	rev := "HEAD"
+	if len(positional) == 1 {
+		rev = positional[0]
+	}
+
+	vc := v1.New()
+	hash, err := vc.ResolveRevision(rev)
+	if err != nil {
+		return err
+	}
+
+	entries, err := vc.Log(hash, v1.LogFilter{MaxCount: 1})
+	if err != nil {
+		return err
+	}
+	entry := entries[0]
+
+	changes, err := vc.Diff(entry.Commit.Parent, hash)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printJSON(newShowJSON(entry, changes))
+	}
+
+	printLog(entries, nil)
+	printChanges(changes)
+	return nil
+}
+
+func init() {
+	Global.Register(&ShowCommand{})
+}
@@ -0,0 +1,122 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type StashCommand struct {
+	BaseCommand
+}
+
+func (c *StashCommand) Name() string { return "stash" }
+func (c *StashCommand) Description() string {
+	return "Shelves local changes: stash push [-m msg] [--include-untracked] [paths], " +
+		"stash list, stash apply|pop|drop [stash@{n}]."
+}
+
+func (c *StashCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "message", Short: "m", Type: ArgString, Description: "description of the stash"},
+		{Name: "include-untracked", Type: ArgBool, Description: "stash untracked files as well"},
+	}
+}
+
+func (c *StashCommand) ExecuteCommand(p map[string][]string) error {
+	vc := v1.New()
+
+	positional := p["positional"]
+	sub := "push"
+	if len(positional) > 0 {
+		sub, positional = positional[0], positional[1:]
+	}
+
+	switch sub {
+	case "push":
+		return c.push(vc, positional, p)
+	case "list":
+		return c.list(vc)
+	case "apply", "pop", "drop":
+		n, err := stashIndex(positional)
+		if err != nil {
+			return err
+		}
+		return c.restore(vc, sub, n)
+	default:
+		return newUsageError("unknown stash subcommand: " + sub)
+	}
+}
+
+func (c *StashCommand) push(vc *v1.VersionControlV1, paths []string, p map[string][]string) error {
+	message := ""
+	if m, ok := p["message"]; ok {
+		message = m[0]
+	}
+
+	_, includeUntracked := p["include-untracked"]
+
+	if _, err := vc.StashPush(message, paths, includeUntracked); err != nil {
+		return err
+	}
+
+	entries, err := vc.StashList()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Saved working directory: %s\n", entries[0].Reason)
+	return nil
+}
+
+func (c *StashCommand) list(vc *v1.VersionControlV1) error {
+	entries, err := vc.StashList()
+	if err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		fmt.Printf("stash@{%d}: %s\n", i, e.Reason)
+	}
+	return nil
+}
+
+func (c *StashCommand) restore(vc *v1.VersionControlV1, sub string, n int) error {
+	switch sub {
+	case "apply":
+		return vc.StashApply(n)
+	case "pop":
+		if err := vc.StashPop(n); err != nil {
+			return err
+		}
+		fmt.Printf("Dropped stash@{%d}\n", n)
+	default:
+		hash, err := vc.StashDrop(n)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dropped stash@{%d} (%s)\n", n, v1.ShortHash(hash))
+	}
+	return nil
+}
+
+// stashIndex parses an optional "stash@{n}" or "n" argument.
+func stashIndex(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	if len(args) > 1 {
+		return 0, newUsageError("expected a single stash entry")
+	}
+
+	s := strings.TrimSuffix(strings.TrimPrefix(args[0], "stash@{"), "}")
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, newUsageError("invalid stash entry: " + args[0])
+	}
+	return n, nil
+}
+
+func init() {
+	Global.Register(&StashCommand{})
+}
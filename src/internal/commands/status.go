@@ -3,6 +3,7 @@ package commands
 import (
 	v1 "MultiRepoVC/src/internal/core/version_control/v1"
 	"fmt"
+	"strconv"
 )
 
 type StatusCommand struct {
@@ -12,23 +13,105 @@ type StatusCommand struct {
 func (c *StatusCommand) Name() string { return "status" }
 
 func (c *StatusCommand) Description() string {
-	return "Shows the working directory status compared to HEAD."
+	return "Shows the working directory status compared to HEAD. " +
+		"--format json and --porcelain print stable machine-readable output. " +
+		"Untracked files, and renames or copies into them, are hidden with status.showUntracked = false. " +
+		"--exit-code exits with status 3 when there are local changes."
 }
 
-func (c *StatusCommand) RequiredArgs() []string { return []string{} }
-func (c *StatusCommand) OptionalArgs() []string { return []string{} }
+func (c *StatusCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "format", Type: ArgString, Description: "json for machine-readable output"},
+		{Name: "porcelain", Type: ArgBool, Description: "print stable line-oriented output"},
+		{Name: "exit-code", Type: ArgBool, Description: "exit with status 3 when the working directory is dirty"},
+	}
+}
 
 func (c *StatusCommand) ExecuteCommand(p map[string][]string) error {
+	format, err := outputFormat(p, "json")
+	if err != nil {
+		return err
+	}
+	_, porcelain := p["porcelain"]
+	if porcelain && format != "" {
+		return newUsageError("--porcelain and --format are mutually exclusive")
+	}
+
+	// An unreadable config, which main already warned about, leaves the
+	// default
 	vc := v1.New()
-	out, err := vc.Status()
+	showUntracked := true
+	if config, err := vc.Config(); err == nil {
+		if showUntracked, err = config.Bool("status.showUntracked", true); err != nil {
+			return err
+		}
+	}
+
+	status, err := vc.Status()
 	if err != nil {
 		return err
 	}
+	if !showUntracked {
+		status = status.WithoutUntracked()
+	}
 
-	fmt.Println(out)
+	switch {
+	case format == "json":
+		return printJSON(newStatusJSON(status))
+	case porcelain:
+		printStatusPorcelain(status)
+	default:
+		fmt.Println(status)
+	}
+
+	if _, ok := p["exit-code"]; ok && !status.Clean() {
+		return &ExitError{Code: ExitDirty}
+	}
 	return nil
 }
 
+// printStatusPorcelain prints one tab-separated line per change; the
+// first field says what the line is (see docs/v1/JSON.md).
+func printStatusPorcelain(s v1.StatusResult) {
+	for _, p := range s.Modified {
+		fmt.Println("M\t" + p)
+	}
+	for _, p := range s.Deleted {
+		fmt.Println("D\t" + p)
+	}
+	for _, r := range s.Renamed {
+		kind := "R"
+		if r.Copy {
+			kind = "C"
+		}
+		fmt.Println(kind + "\t" + strconv.Itoa(r.Similarity) + "\t" + r.From + "\t" + r.To)
+	}
+	for _, p := range s.Untracked {
+		fmt.Println("?\t" + p)
+	}
+	for _, n := range s.NestedRepos {
+		fmt.Println("N\t" + n.Kind + "\t" + n.RepoID + "\t" + n.Path + "\t" + n.OldPath)
+	}
+	for _, w := range s.Warnings {
+		fmt.Println("W\t" + w)
+	}
+}
+
+// outputFormat returns the value of --format ("" when absent) after
+// checking it against the supported formats.
+func outputFormat(p map[string][]string, supported ...string) (string, error) {
+	f, ok := p["format"]
+	if !ok {
+		return "", nil
+	}
+	for _, s := range supported {
+		if f[0] == s {
+			return s, nil
+		}
+	}
+	return "", newUsageError("unknown --format: " + f[0])
+}
+
 func init() {
 	Global.Register(&StatusCommand{})
 }
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	v1 "MultiRepoVC/src/internal/core/version_control/v1"
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"fmt"
+	"path/filepath"
+)
+
+type WorkspaceCommand struct {
+	BaseCommand
+}
+
+func (c *WorkspaceCommand) Name() string { return "workspace" }
+func (c *WorkspaceCommand) Description() string {
+	return "Exports the nested repo hierarchy to a manifest or recreates it from one: " +
+		"workspace export [--output file] (stdout by default), workspace sync <manifest> [--source-root dir] [--force]."
+}
+
+func (c *WorkspaceCommand) Args() []ArgSpec {
+	return []ArgSpec{
+		{Name: "output", Type: ArgString, Description: "export: manifest file to write instead of stdout"},
+		{Name: "source-root", Type: ArgString, Description: "sync: directory to copy repos from"},
+		{Name: "force", Type: ArgBool, Description: "sync: overwrite existing repos"},
+	}
+}
+
+func (c *WorkspaceCommand) ExecuteCommand(p map[string][]string) error {
+	positional := p["positional"]
+	if len(positional) == 0 {
+		return newUsageError("missing subcommand: export or sync")
+	}
+
+	switch positional[0] {
+	case "export":
+		return c.export(p)
+	case "sync":
+		if len(positional) < 2 {
+			return newUsageError("missing manifest: mrvc workspace sync <manifest>")
+		}
+		return c.sync(positional[1], p)
+	default:
+		return newUsageError("unknown workspace subcommand: " + positional[0])
+	}
+}
+
+// export prints the manifest, or writes it to --output. Writing a file
+// by default would drop an untracked file into the repo root.
+func (c *WorkspaceCommand) export(p map[string][]string) error {
+	manifest, err := v1.ExportWorkspace(fs.GetCurrentDir())
+	if err != nil {
+		return err
+	}
+
+	o, ok := p["output"]
+	if !ok || len(o) == 0 {
+		return printJSON(manifest)
+	}
+	output := o[0]
+
+	if err := fs.WriteJSON(output, manifest); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d repositories to %s\n", len(manifest.Repos), output)
+	return nil
+}
+
+// sync recreates the manifest's hierarchy below the current directory.
+// Repos are processed parents first so nested repos land inside an
+// already populated parent.
+func (c *WorkspaceCommand) sync(manifestPath string, p map[string][]string) error {
+	var manifest model.WorkspaceManifest
+	if err := fs.ReadJSON(manifestPath, &manifest); err != nil {
+		return err
+	}
+
+	if manifest.Version != v1.WorkspaceManifestVersion {
+		return fmt.Errorf("unsupported manifest version: %d", manifest.Version)
+	}
+
+	sourceRoot := ""
+	if s, ok := p["source-root"]; ok && len(s) > 0 {
+		sourceRoot = s[0]
+	}
+	_, force := p["force"]
+
+	root := fs.GetCurrentDir()
+	manifestDir := filepath.Dir(fs.NormalizePath(manifestPath))
+
+	for _, entry := range manifest.Repos {
+		switch {
+		case sourceRoot != "":
+			entry.Source = filepath.Join(sourceRoot, entry.Path)
+		case !filepath.IsAbs(entry.Source):
+			entry.Source = filepath.Join(manifestDir, entry.Source)
+		}
+
+		dest := filepath.Join(root, entry.Path)
+
+		action, err := v1.SyncRepo(entry, dest, force)
+		if err != nil {
+			return fmt.Errorf("sync of %s (%s) failed: %w", entry.Name, entry.Path, err)
+		}
+
+		fmt.Printf("%-12s %s (%s)\n", action, entry.Name, entry.Path)
+	}
+
+	return nil
+}
+
+func init() {
+	Global.Register(&WorkspaceCommand{})
+}
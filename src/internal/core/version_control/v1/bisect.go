@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ======================================================================
+// BISECT
+//
+// Bisect binary-searches the first-parent chain between a good and a bad
+// commit for the commit that introduced a regression. Every step checks
+// out the midpoint of the commits still in question; marking it good or
+// bad halves the range. The session lives in .mrvc/bisect.json until
+// bisect reset returns to the original HEAD.
+// ======================================================================
+
+// ErrNoBisect is returned when no bisect session is running.
+var ErrNoBisect = errors.New("not bisecting: run 'mrvc bisect start <bad> <good>' first")
+
+// BisectStatus describes the state of the search after a step.
+type BisectStatus struct {
+	Culprit   string   // first bad commit, once found
+	Current   string   // commit checked out for testing
+	Remaining int      // commits left to test after Current
+	Steps     int      // roughly how many more tests are needed after Current
+	Skipped   []string // when only skipped commits are left: the suspects
+}
+
+// Done reports whether the search is over, with or without a culprit.
+func (s BisectStatus) Done() bool {
+	return s.Culprit != "" || len(s.Skipped) > 0
+}
+
+func bisectPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".mrvc", "bisect.json")
+}
+
+func readBisect(repoRoot string) (model.BisectState, error) {
+	var state model.BisectState
+	if !fs.FileExists(bisectPath(repoRoot)) {
+		return state, ErrNoBisect
+	}
+	err := fs.ReadJSON(bisectPath(repoRoot), &state)
+	return state, err
+}
+
+// BisectStart begins a session between bad and an ancestor good.
+func (v *VersionControlV1) BisectStart(bad, good string) (BisectStatus, error) {
+	unlock, err := v.lock()
+	if err != nil {
+		return BisectStatus{}, err
+	}
+	defer unlock()
+
+	if fs.FileExists(bisectPath(v.root)) {
+		return BisectStatus{}, errors.New("already bisecting: run 'mrvc bisect reset' first")
+	}
+	if err := ensureNoSequence(v.root); err != nil {
+		return BisectStatus{}, err
+	}
+
+	state := model.BisectState{OrigHead: readHEAD(v.root), Bad: bad, Good: good}
+	return v.bisectStep(state)
+}
+
+// BisectMark records a verdict ("good", "bad" or "skip") for commitHash
+// and checks out the next commit to test.
+func (v *VersionControlV1) BisectMark(verdict, commitHash string) (BisectStatus, error) {
+	unlock, err := v.lock()
+	if err != nil {
+		return BisectStatus{}, err
+	}
+	defer unlock()
+
+	state, err := readBisect(v.root)
+	if err != nil {
+		return BisectStatus{}, err
+	}
+
+	candidates, err := v.bisectCandidates(state)
+	if err != nil {
+		return BisectStatus{}, err
+	}
+	if !slices.Contains(candidates, commitHash) {
+		return BisectStatus{}, errors.New(ShortHash(commitHash) + " is not between the good and the bad commit")
+	}
+
+	switch verdict {
+	case "good":
+		state.Good = commitHash
+	case "bad":
+		state.Bad = commitHash
+	case "skip":
+		if !slices.Contains(state.Skipped, commitHash) {
+			state.Skipped = append(state.Skipped, commitHash)
+		}
+	default:
+		return BisectStatus{}, errors.New("unknown bisect verdict: " + verdict)
+	}
+
+	return v.bisectStep(state)
+}
+
+// BisectReset ends the session and checks out the original HEAD.
+func (v *VersionControlV1) BisectReset() error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := readBisect(v.root)
+	if err != nil {
+		return err
+	}
+
+	if readHEAD(v.root) != state.OrigHead {
+		if err := v.checkoutCommit(state.OrigHead, false, "bisect: reset"); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(bisectPath(v.root))
+}
+
+// bisectStep checks out the midpoint of the untested candidates, or
+// reports the result when none is left, and then saves state. A failed
+// checkout leaves the saved state as it was.
+func (v *VersionControlV1) bisectStep(state model.BisectState) (BisectStatus, error) {
+	candidates, err := v.bisectCandidates(state)
+	if err != nil {
+		return BisectStatus{}, err
+	}
+
+	// The bad commit itself is already known
+	var testable, skipped []string
+	for _, c := range candidates[:len(candidates)-1] {
+		if slices.Contains(state.Skipped, c) {
+			skipped = append(skipped, c)
+		} else {
+			testable = append(testable, c)
+		}
+	}
+
+	if len(testable) == 0 {
+		if err := fs.WriteJSON(bisectPath(v.root), state); err != nil {
+			return BisectStatus{}, err
+		}
+		if len(skipped) > 0 {
+			return BisectStatus{Skipped: append(skipped, state.Bad)}, nil
+		}
+		return BisectStatus{Culprit: state.Bad}, nil
+	}
+
+	mid := testable[len(testable)/2]
+	if readHEAD(v.root) != mid {
+		if err := v.checkoutCommit(mid, false, "bisect: checkout "+ShortHash(mid)); err != nil {
+			return BisectStatus{}, err
+		}
+	}
+
+	if err := fs.WriteJSON(bisectPath(v.root), state); err != nil {
+		return BisectStatus{}, err
+	}
+
+	remaining := len(testable) - 1
+	return BisectStatus{
+		Current:   mid,
+		Remaining: remaining,
+		Steps:     max(bits.Len(uint(remaining))-1, 0),
+	}, nil
+}
+
+// bisectCandidates lists the commits after Good up to and including Bad
+// on Bad's first-parent chain, oldest first.
+func (v *VersionControlV1) bisectCandidates(state model.BisectState) ([]string, error) {
+	var chain []string
+	for h := state.Bad; h != state.Good; {
+		if h == "" {
+			return nil, errors.New(ShortHash(state.Good) + " is not an ancestor of " + ShortHash(state.Bad))
+		}
+		chain = append(chain, h)
+
+		commit, err := readCommit(v.root, h)
+		if err != nil {
+			return nil, err
+		}
+		h = commit.Parent
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New(ShortHash(state.Bad) + " cannot be both good and bad")
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
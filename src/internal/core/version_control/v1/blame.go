@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/utils/diff"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ======================================================================
+// BLAME
+//
+// Blame attributes every line of a file to the commit that introduced
+// it. Starting at the requested commit, the lines still unattributed are
+// followed through first parents: each step diffs the parent's version
+// of the file against the child's, lines matched in the parent move on
+// to it, and the remaining lines belong to the child. A commit whose
+// parent lacks the file owns everything left.
+// ======================================================================
+
+// BlameLine is one line of a blamed file.
+type BlameLine struct {
+	Line     int    // 1-based line number in the blamed revision
+	OrigLine int    // 1-based line number in Commit
+	Content  string // line without its newline
+	Commit   string // commit that introduced the line
+	Author   string
+	Time     int64 // author timestamp in milliseconds
+	Summary  string
+}
+
+// pendingLine tracks an unattributed line: final index in the blamed
+// revision and its index in the commit currently examined.
+type pendingLine struct {
+	final, current int
+}
+
+// Blame annotates path (relative to the repository root or absolute) as
+// of commitHash.
+func (v *VersionControlV1) Blame(path, commitHash string) ([]BlameLine, error) {
+	rel, err := v.repoRelPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := commitFiles(v.root, commitHash)
+	if err != nil {
+		return nil, err
+	}
+	blob, ok := files[rel]
+	if !ok {
+		return nil, errors.New("no such path '" + rel + "' in " + ShortHash(commitHash))
+	}
+
+	lines, err := blobLines(v.root, blob)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BlameLine, len(lines))
+	pending := make([]pendingLine, len(lines))
+	for i, line := range lines {
+		result[i] = BlameLine{Line: i + 1, Content: strings.TrimSuffix(line, "\n")}
+		pending[i] = pendingLine{final: i, current: i}
+	}
+
+	current, currentBlob, currentLines := commitHash, blob, lines
+
+	for len(pending) > 0 {
+		commit, err := readCommit(v.root, current)
+		if err != nil {
+			return nil, err
+		}
+
+		attribute := func(p pendingLine) {
+			ms, _ := strconv.ParseInt(commit.Author.Timestamp, 10, 64)
+			line := &result[p.final]
+			line.OrigLine = p.current + 1
+			line.Commit = current
+			line.Author = commit.Author.Name
+			line.Time = ms
+			line.Summary = subject(commit.Message)
+		}
+
+		parentBlob := ""
+		if commit.Parent != "" {
+			parentFiles, err := commitFiles(v.root, commit.Parent)
+			if err != nil {
+				return nil, err
+			}
+			parentBlob = parentFiles[rel]
+		}
+
+		// Introduced here: everything left belongs to this commit
+		if parentBlob == "" {
+			for _, p := range pending {
+				attribute(p)
+			}
+			break
+		}
+
+		// Untouched by this commit
+		if parentBlob == currentBlob {
+			current = commit.Parent
+			continue
+		}
+
+		parentLines, err := blobLines(v.root, parentBlob)
+		if err != nil {
+			return nil, err
+		}
+
+		toParent := make(map[int]int)
+		for _, m := range diff.Matches(parentLines, currentLines) {
+			toParent[m.B] = m.A
+		}
+
+		var next []pendingLine
+		for _, p := range pending {
+			if idx, ok := toParent[p.current]; ok {
+				next = append(next, pendingLine{final: p.final, current: idx})
+			} else {
+				attribute(p)
+			}
+		}
+
+		pending = next
+		current, currentBlob, currentLines = commit.Parent, parentBlob, parentLines
+	}
+
+	return result, nil
+}
+
+// blobLines reads a blob and splits it into lines.
+func blobLines(repoRoot, hash string) ([]string, error) {
+	content, err := readObject(repoRoot, hash)
+	if err != nil {
+		return nil, err
+	}
+	return diff.SplitLines(string(content)), nil
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ======================================================================
+// CHECKOUT
+//
+// Checkout rewrites the tracked part of the working directory so it
+// matches another commit's snapshot:
+//   • files of the target snapshot are written
+//   • files tracked by HEAD but absent from the target are removed
+//   • untracked files and nested repositories are never touched
+//
+// Unless forced, checkout refuses to run when it would overwrite or
+// delete local modifications.
+// ======================================================================
+
+// CheckoutCommit makes the working directory match commitHash and moves
+// HEAD to it.
+func (v *VersionControlV1) CheckoutCommit(commitHash string, force bool) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return v.checkoutCommit(commitHash, force, "checkout: moving to "+commitHash)
+}
+
+// checkoutCommit is CheckoutCommit for callers already holding the lock.
+// reason is recorded in the reflog.
+func (v *VersionControlV1) checkoutCommit(commitHash string, force bool, reason string) error {
+	head := readHEAD(v.root)
+	if err := v.checkoutTree(head, commitHash, force); err != nil {
+		return err
+	}
+	return updateHEAD(v.root, head, commitHash, v.defaultAuthor(), reason)
+}
+
+// checkoutTree moves the working directory from the snapshot of current
+// to the snapshot of target without touching HEAD.
+func (v *VersionControlV1) checkoutTree(current, target string, force bool) error {
+	currentFiles, err := commitFiles(v.root, current)
+	if err != nil {
+		return err
+	}
+
+	targetFiles, err := commitFiles(v.root, target)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		if err := v.checkOverwrites(currentFiles, targetFiles); err != nil {
+			return err
+		}
+	}
+
+	return v.writeSnapshot(currentFiles, targetFiles, force)
+}
+
+// checkOverwrites fails when switching from current to target would
+// lose local changes or clobber an untracked file.
+func (v *VersionControlV1) checkOverwrites(current, target map[string]string) error {
+	var conflicts []string
+
+	paths := make(map[string]bool)
+	for p := range current {
+		paths[p] = true
+	}
+	for p := range target {
+		paths[p] = true
+	}
+
+	for rel := range paths {
+		currentHash, tracked := current[rel]
+		targetHash, wanted := target[rel]
+
+		// Unchanged between both snapshots → local edits survive as-is
+		if tracked && wanted && currentHash == targetHash {
+			continue
+		}
+
+		abs := filepath.Join(v.root, rel)
+		if v.insideNestedRepo(rel) || !fs.FileExists(abs) {
+			continue
+		}
+
+		diskHash, err := fs.CalculateFileHash(abs)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case tracked && diskHash != currentHash:
+			conflicts = append(conflicts, rel)
+		case !tracked && diskHash != targetHash:
+			conflicts = append(conflicts, rel)
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	sort.Strings(conflicts)
+	return errors.New("local changes would be overwritten:\n  " + strings.Join(conflicts, "\n  "))
+}
+
+// writeSnapshot writes every target file that differs on disk and
+// deletes files only tracked by current.
+func (v *VersionControlV1) writeSnapshot(current, target map[string]string, force bool) error {
+	for rel, hash := range target {
+		if v.insideNestedRepo(rel) {
+			continue
+		}
+
+		abs := filepath.Join(v.root, rel)
+
+		// Keep local edits of files the switch does not touch
+		if !force && current[rel] == hash && fs.FileExists(abs) {
+			continue
+		}
+
+		if fs.FileExists(abs) {
+			if diskHash, err := fs.CalculateFileHash(abs); err == nil && diskHash == hash {
+				continue
+			}
+		}
+
+		content, err := readObject(v.root, hash)
+		if err != nil {
+			return err
+		}
+
+		if err := fs.CreateDir(filepath.Dir(abs)); err != nil {
+			return err
+		}
+
+		if err := fs.WriteFileAtomic(abs, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	for rel := range current {
+		if _, keep := target[rel]; keep || v.insideNestedRepo(rel) {
+			continue
+		}
+
+		abs := filepath.Join(v.root, rel)
+		if err := os.Remove(abs); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		v.removeEmptyParents(filepath.Dir(abs))
+	}
+
+	return nil
+}
+
+// insideNestedRepo reports whether rel lies inside a nested repository.
+func (v *VersionControlV1) insideNestedRepo(rel string) bool {
+	dir := filepath.Dir(filepath.Join(v.root, rel))
+	for fs.NormalizePath(dir) != v.root {
+		if fs.IsDirPresent(filepath.Join(dir, ".mrvc")) {
+			return true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+	return false
+}
+
+// removeEmptyParents deletes dir and its ancestors up to the repo root
+// for as long as they are empty.
+func (v *VersionControlV1) removeEmptyParents(dir string) {
+	for fs.NormalizePath(dir) != v.root {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+)
+
+// ======================================================================
+// CHERRY-PICK
+//
+// CherryPick replays the change each commit made against its parent onto
+// HEAD, oldest argument first, keeping the original author and message.
+// ======================================================================
+
+// CherryPick applies commits in order. With recordOrigin, every message
+// gets a "(cherry picked from commit <hash>)" line. Conflicts stop with
+// a *ConflictError; resolve them and call CherryPickContinue, or use
+// CherryPickSkip / CherryPickAbort.
+func (v *VersionControlV1) CherryPick(commits []string, recordOrigin bool) error {
+	if len(commits) == 0 {
+		return errors.New("nothing to cherry-pick: no commits given")
+	}
+
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := ensureNoSequence(v.root); err != nil {
+		return err
+	}
+
+	head := readHEAD(v.root)
+	if head == "" {
+		return errors.New("cannot cherry-pick onto an empty repository")
+	}
+
+	state := newSequence("cherry-pick", head, commits)
+	state.RecordOrigin = recordOrigin
+	return v.runSequence(state, v.cherryPick)
+}
+
+// CherryPickContinue commits the resolved commit and picks the rest.
+func (v *VersionControlV1) CherryPickContinue() error {
+	return v.continueOperation("cherry-pick", v.cherryPick)
+}
+
+// CherryPickSkip drops the conflicted commit and picks the rest.
+func (v *VersionControlV1) CherryPickSkip() error {
+	return v.skipOperation("cherry-pick", v.cherryPick)
+}
+
+// CherryPickAbort drops the whole cherry-pick, including commits already
+// picked, and restores HEAD and the working files.
+func (v *VersionControlV1) CherryPickAbort() error {
+	return v.abortOperation("cherry-pick")
+}
+
+// cherryPick builds the change parent → commit.
+func (v *VersionControlV1) cherryPick(state model.SequencerState, commitHash string) (pick, error) {
+	commit, err := readCommit(v.root, commitHash)
+	if err != nil {
+		return pick{}, err
+	}
+
+	message := commit.Message
+	if state.RecordOrigin {
+		message += "\n\n(cherry picked from commit " + commitHash + ")"
+	}
+
+	return pick{
+		Commit:  commitHash,
+		Base:    commit.Parent,
+		Theirs:  commitHash,
+		Label:   ShortHash(commitHash) + " (" + subject(commit.Message) + ")",
+		Message: message,
+		Author:  commit.Author,
+	}, nil
+}
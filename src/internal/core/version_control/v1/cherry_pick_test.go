@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// stoppedCherryPick picks two commits adding a.txt and b.txt onto c1
+// while an untracked b.txt is in the way, so the second pick fails after
+// the first was committed. It returns c1 and the commit adding b.txt.
+func stoppedCherryPick(t *testing.T, v *VersionControlV1) (string, string) {
+	t.Helper()
+
+	c1 := commitSnapshot(t, v, "one", map[string]string{"file.txt": "one"})
+	c2 := commitSnapshot(t, v, "add a", map[string]string{"file.txt": "one", "a.txt": "a"})
+	c3 := commitSnapshot(t, v, "add b", map[string]string{"file.txt": "one", "a.txt": "a", "b.txt": "b"})
+
+	if err := v.Reset(c1, "HEAD~2", ResetHard); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, v, "b.txt", "mine")
+
+	err := v.CherryPick([]string{c2, c3}, false)
+	var conflict *ConflictError
+	if err == nil || errors.As(err, &conflict) {
+		t.Fatalf("CherryPick() = %v, want a failure without conflicts", err)
+	}
+
+	state, err := readSequencer(v.root)
+	if err != nil {
+		t.Fatalf("no cherry-pick state after the failed pick: %v", err)
+	}
+	if state.OrigHead != c1 || state.Current != c3 || len(state.Todo) != 0 {
+		t.Fatalf("state = %+v, want orig_head %s and current %s", state, ShortHash(c1), ShortHash(c3))
+	}
+	return c1, c3
+}
+
+func writeFile(t *testing.T, v *VersionControlV1, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(v.Root(), name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkFile(t *testing.T, v *VersionControlV1, name, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(v.Root(), name))
+	switch {
+	case want == "" && !os.IsNotExist(err):
+		t.Errorf("%s exists (%q, %v), want it removed", name, data, err)
+	case want != "" && (err != nil || string(data) != want):
+		t.Errorf("%s = %q, %v, want %q", name, data, err, want)
+	}
+}
+
+func checkNoSequence(t *testing.T, v *VersionControlV1) {
+	t.Helper()
+	if state, err := readSequencer(v.root); !errors.Is(err, ErrNoSequence) {
+		t.Errorf("sequencer state left behind: %+v, %v", state, err)
+	}
+}
+
+func TestCherryPickStoppedAbort(t *testing.T) {
+	v := newTestRepo(t)
+	c1, _ := stoppedCherryPick(t, v)
+
+	if err := v.CherryPickAbort(); err != nil {
+		t.Fatal(err)
+	}
+
+	if head := readHEAD(v.root); head != c1 {
+		t.Errorf("HEAD = %s after abort, want %s", ShortHash(head), ShortHash(c1))
+	}
+	checkFile(t, v, "a.txt", "")
+	checkFile(t, v, "b.txt", "mine")
+	checkNoSequence(t, v)
+}
+
+func TestCherryPickStoppedContinue(t *testing.T) {
+	v := newTestRepo(t)
+	stoppedCherryPick(t, v)
+
+	if err := os.Remove(filepath.Join(v.Root(), "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.CherryPickContinue(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"file.txt": "one", "a.txt": "a", "b.txt": "b"}
+	if got := headFiles(t, v); !reflect.DeepEqual(got, want) {
+		t.Errorf("HEAD files = %v, want %v", got, want)
+	}
+	checkFile(t, v, "b.txt", "b")
+	checkNoSequence(t, v)
+}
+
+func TestCherryPickStoppedSkip(t *testing.T) {
+	v := newTestRepo(t)
+	stoppedCherryPick(t, v)
+
+	if err := v.CherryPickSkip(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"file.txt": "one", "a.txt": "a"}
+	if got := headFiles(t, v); !reflect.DeepEqual(got, want) {
+		t.Errorf("HEAD files = %v, want %v", got, want)
+	}
+	checkFile(t, v, "b.txt", "mine")
+	checkNoSequence(t, v)
+}
+
+// A failure before anything was committed leaves nothing to resume.
+func TestCherryPickFirstPickFails(t *testing.T) {
+	v := newTestRepo(t)
+	c1 := commitSnapshot(t, v, "one", map[string]string{"file.txt": "one"})
+	c2 := commitSnapshot(t, v, "add b", map[string]string{"file.txt": "one", "b.txt": "b"})
+
+	if err := v.Reset(c1, "HEAD~1", ResetHard); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, v, "b.txt", "mine")
+
+	if err := v.CherryPick([]string{c2}, false); err == nil {
+		t.Fatal("CherryPick() succeeded over an untracked file")
+	}
+	if head := readHEAD(v.root); head != c1 {
+		t.Errorf("HEAD = %s, want %s", ShortHash(head), ShortHash(c1))
+	}
+	checkFile(t, v, "b.txt", "mine")
+	checkNoSequence(t, v)
+}
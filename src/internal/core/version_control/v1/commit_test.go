@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitCommitter(t *testing.T) {
+	author := NewIdentity("author", "author@example.com")
+
+	tests := []struct {
+		name          string
+		userName      string // MRVC_AUTHOR_NAME
+		globalConfig  string
+		wantCommitter string
+		wantErr       string
+	}{
+		{name: "configured user", userName: "tester", wantCommitter: "tester"},
+		{name: "user from config", globalConfig: "user.name = configured\n", wantCommitter: "configured"},
+		{name: "no user falls back to the author", wantCommitter: "author"},
+		{name: "broken config", globalConfig: "not a setting\n", wantErr: "expected key = value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestRepo(t)
+			t.Setenv(envAuthorName, tt.userName)
+			t.Setenv(envAuthorEmail, "")
+			if tt.globalConfig != "" {
+				if err := os.WriteFile(os.Getenv("MRVC_CONFIG_GLOBAL"), []byte(tt.globalConfig), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			path := filepath.Join(v.Root(), "file.txt")
+			if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := v.Commit("message", author, []string{path})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Commit() = %v, want error %q", err, tt.wantErr)
+				}
+				if head := readHEAD(v.root); head != "" {
+					t.Errorf("Commit() failed but wrote %s", ShortHash(head))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			commit, err := readCommit(v.root, readHEAD(v.root))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if commit.Author != author {
+				t.Errorf("author = %+v, want %+v", commit.Author, author)
+			}
+			if got := commit.Committer; got.Name != tt.wantCommitter {
+				t.Errorf("committer = %+v, want %s", got, tt.wantCommitter)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/utils/fs"
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ======================================================================
+// CONFIGURATION
+//
+// Settings live in up to three files, read in this order so that later
+// files override earlier ones:
+//   system  /etc/mrvc/config (%ProgramData%\mrvc\config on Windows)
+//   global  ~/.config/mrvc/config ($XDG_CONFIG_HOME/mrvc/config)
+//   local   .mrvc/config of the repository
+// MRVC_CONFIG_SYSTEM and MRVC_CONFIG_GLOBAL point the first two
+// elsewhere. Every file holds one "section.name = value" per line;
+// blank lines and lines starting with # or ; are ignored. Keys are
+// case-insensitive.
+// ======================================================================
+
+// ConfigScope names one of the configuration files.
+type ConfigScope string
+
+const (
+	ScopeSystem ConfigScope = "system"
+	ScopeGlobal ConfigScope = "global"
+	ScopeLocal  ConfigScope = "local"
+)
+
+// configScopes lists the scopes from lowest to highest precedence.
+var configScopes = []ConfigScope{ScopeSystem, ScopeGlobal, ScopeLocal}
+
+// ErrConfigKeyNotSet is returned when a key is set in no config file.
+var ErrConfigKeyNotSet = errors.New("config key not set")
+
+// ConfigEntry is one key of a config file.
+type ConfigEntry struct {
+	Key    string
+	Value  string
+	Scope  ConfigScope
+	Origin string // path of the file the entry was read from
+}
+
+// Config is the merged view of every config file.
+type Config struct {
+	entries map[string]ConfigEntry // lower-cased key → winning entry
+}
+
+// ConfigPath returns the file of scope. The local scope needs the root
+// of a repository.
+func ConfigPath(scope ConfigScope, repoRoot string) (string, error) {
+	switch scope {
+	case ScopeSystem:
+		if p := os.Getenv("MRVC_CONFIG_SYSTEM"); p != "" {
+			return p, nil
+		}
+		if runtime.GOOS == "windows" {
+			return filepath.Join(os.Getenv("ProgramData"), "mrvc", "config"), nil
+		}
+		return "/etc/mrvc/config", nil
+
+	case ScopeGlobal:
+		if p := os.Getenv("MRVC_CONFIG_GLOBAL"); p != "" {
+			return p, nil
+		}
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			return filepath.Join(xdg, "mrvc", "config"), nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".config", "mrvc", "config"), nil
+
+	case ScopeLocal:
+		if repoRoot == "" || !fs.IsDirPresent(filepath.Join(repoRoot, ".mrvc")) {
+			return "", errors.New("not in a mrvc repository")
+		}
+		return filepath.Join(repoRoot, ".mrvc", "config"), nil
+	}
+
+	return "", errors.New("unknown config scope: " + string(scope))
+}
+
+// LoadConfig reads the system, global and local config files. Missing
+// files are skipped, as is the local scope outside of a repository
+// (repoRoot empty or not a repository).
+func LoadConfig(repoRoot string) (*Config, error) {
+	c := &Config{entries: make(map[string]ConfigEntry)}
+
+	for _, scope := range configScopes {
+		entries, err := ReadConfigFile(scope, repoRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			c.entries[strings.ToLower(e.Key)] = e
+		}
+	}
+	return c, nil
+}
+
+// Config returns the configuration of this repository.
+func (v *VersionControlV1) Config() (*Config, error) {
+	return LoadConfig(v.root)
+}
+
+// ReadConfigFile returns the entries of one scope's file in file order.
+// A missing file, or the local scope outside of a repository, has no
+// entries.
+func ReadConfigFile(scope ConfigScope, repoRoot string) ([]ConfigEntry, error) {
+	path, err := ConfigPath(scope, repoRoot)
+	if err != nil {
+		if scope == ScopeLocal {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []ConfigEntry
+	seen := make(map[string]int)
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		key, value, ok, err := parseConfigLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		if !ok {
+			continue
+		}
+
+		e := ConfigEntry{Key: key, Value: value, Scope: scope, Origin: path}
+		if i, dup := seen[strings.ToLower(key)]; dup {
+			entries[i] = e // the last occurrence in a file wins
+			continue
+		}
+		seen[strings.ToLower(key)] = len(entries)
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// parseConfigLine splits "key = value". ok is false for blank lines and
+// comments.
+func parseConfigLine(line string) (key, value string, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' || line[0] == ';' {
+		return "", "", false, nil
+	}
+
+	key, value, found := strings.Cut(line, "=")
+	if !found {
+		return "", "", false, errors.New("expected key = value: " + line)
+	}
+
+	key = strings.TrimSpace(key)
+	if err := ValidateConfigKey(key); err != nil {
+		return "", "", false, err
+	}
+	return key, strings.TrimSpace(value), true, nil
+}
+
+// ValidateConfigKey checks that key has the form section.name, using
+// letters, digits, "-" and "_".
+func ValidateConfigKey(key string) error {
+	section, name, found := strings.Cut(key, ".")
+	if !found || section == "" || name == "" {
+		return errors.New("invalid config key (expected section.name): " + key)
+	}
+
+	for _, r := range key {
+		if r != '.' && r != '-' && r != '_' &&
+			(r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return errors.New("invalid character in config key: " + key)
+		}
+	}
+	return nil
+}
+
+// Get returns the winning entry of key.
+func (c *Config) Get(key string) (ConfigEntry, bool) {
+	e, ok := c.entries[strings.ToLower(key)]
+	return e, ok
+}
+
+// Value returns the value of key, or def when it is not set.
+func (c *Config) Value(key, def string) string {
+	if e, ok := c.Get(key); ok {
+		return e.Value
+	}
+	return def
+}
+
+// Bool returns key as a boolean, or def when it is not set.
+func (c *Config) Bool(key string, def bool) (bool, error) {
+	e, ok := c.Get(key)
+	if !ok {
+		return def, nil
+	}
+	b, err := ParseConfigBool(e.Value)
+	if err != nil {
+		return def, fmt.Errorf("%s (%s): %w", key, e.Origin, err)
+	}
+	return b, nil
+}
+
+// Int returns key as an integer, or def when it is not set.
+func (c *Config) Int(key string, def int) (int, error) {
+	e, ok := c.Get(key)
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(e.Value)
+	if err != nil {
+		return def, fmt.Errorf("%s (%s): invalid integer: %s", key, e.Origin, e.Value)
+	}
+	return n, nil
+}
+
+// Duration returns key as a duration ("500ms", "2m"), or def when it is
+// not set.
+func (c *Config) Duration(key string, def time.Duration) (time.Duration, error) {
+	e, ok := c.Get(key)
+	if !ok {
+		return def, nil
+	}
+	d, err := time.ParseDuration(e.Value)
+	if err != nil {
+		return def, fmt.Errorf("%s (%s): invalid duration: %s", key, e.Origin, e.Value)
+	}
+	return d, nil
+}
+
+// Entries returns the winning entry of every key, sorted by key.
+func (c *Config) Entries() []ConfigEntry {
+	out := make([]ConfigEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return strings.ToLower(out[i].Key) < strings.ToLower(out[j].Key)
+	})
+	return out
+}
+
+// ParseConfigBool accepts true/false, yes/no, on/off and 1/0.
+func ParseConfigBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, nil
+	case "false", "no", "off", "0":
+		return false, nil
+	}
+	return false, errors.New("invalid boolean: " + s)
+}
+
+// NormalizeConfigValue checks value against typ ("bool", "int" or
+// "duration"; "" accepts anything) and returns its canonical form.
+func NormalizeConfigValue(typ, value string) (string, error) {
+	switch typ {
+	case "":
+		return value, nil
+	case "bool":
+		b, err := ParseConfigBool(value)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", errors.New("invalid integer: " + value)
+		}
+		return strconv.Itoa(n), nil
+	case "duration":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return "", errors.New("invalid duration: " + value)
+		}
+		return d.String(), nil
+	}
+	return "", errors.New("unknown config type: " + typ + " (use bool, int or duration)")
+}
+
+// SetConfig sets key to value in the file of scope, replacing an earlier
+// value in place and keeping every other line.
+func SetConfig(scope ConfigScope, repoRoot, key, value string) error {
+	if err := ValidateConfigKey(key); err != nil {
+		return err
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.New("config values cannot span lines")
+	}
+
+	return rewriteConfig(scope, repoRoot, func(lines []string) ([]string, error) {
+		line := key + " = " + value
+
+		replaced := false
+		out := lines[:0]
+		for _, l := range lines {
+			if k, _, ok, _ := parseConfigLine(l); ok && strings.EqualFold(k, key) {
+				if replaced {
+					continue // drop duplicates
+				}
+				l, replaced = line, true
+			}
+			out = append(out, l)
+		}
+
+		if !replaced {
+			out = append(out, line)
+		}
+		return out, nil
+	})
+}
+
+// UnsetConfig removes key from the file of scope, failing with
+// ErrConfigKeyNotSet when it is not there.
+func UnsetConfig(scope ConfigScope, repoRoot, key string) error {
+	return rewriteConfig(scope, repoRoot, func(lines []string) ([]string, error) {
+		removed := false
+		out := lines[:0]
+		for _, l := range lines {
+			if k, _, ok, _ := parseConfigLine(l); ok && strings.EqualFold(k, key) {
+				removed = true
+				continue
+			}
+			out = append(out, l)
+		}
+
+		if !removed {
+			return nil, fmt.Errorf("%w: %s (%s)", ErrConfigKeyNotSet, key, scope)
+		}
+		return out, nil
+	})
+}
+
+// rewriteConfig applies edit to the lines of scope's file and writes the
+// result atomically, creating the file if needed.
+func rewriteConfig(scope ConfigScope, repoRoot string, edit func([]string) ([]string, error)) error {
+	path, err := ConfigPath(scope, repoRoot)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if len(data) == 0 {
+			lines = nil
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	lines, err = edit(lines)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var out []byte
+	for _, l := range lines {
+		out = append(append(out, l...), '\n')
+	}
+	return fs.WriteFileAtomic(path, out, 0644)
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A malformed global config only matters where a value is needed that
+// nothing else provides.
+func TestBrokenGlobalConfig(t *testing.T) {
+	v := newTestRepo(t)
+	commitContent(t, v, "one")
+
+	config := "core.lockTimeout = 1s\nthis line is not a setting\n"
+	if err := os.WriteFile(os.Getenv("MRVC_CONFIG_GLOBAL"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Config(); err == nil {
+		t.Fatal("Config() read a malformed file without error")
+	}
+
+	unlock, err := v.lock()
+	if err != nil {
+		t.Fatalf("lock() = %v, want the default timeout", err)
+	}
+	unlock()
+
+	if _, err := v.Status(); err != nil {
+		t.Errorf("Status() = %v", err)
+	}
+
+	// The environment still names the user
+	id, err := v.Identity()
+	if err != nil || id.Name != "tester" || id.Email != "tester@example.com" {
+		t.Errorf("Identity() = %+v, %v, want tester from the environment", id, err)
+	}
+	commitContent(t, v, "two")
+
+	t.Setenv(envAuthorEmail, "")
+	if id, err := v.Identity(); err != nil || id.Name != "tester" || id.Email != "" {
+		t.Errorf("Identity() = %+v, %v, want tester without an email", id, err)
+	}
+
+	t.Setenv(envAuthorName, "")
+	if id, err := v.Identity(); err == nil {
+		t.Errorf("Identity() = %+v, want the config error", id)
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{config: "", want: "0s"},
+		{config: "core.lockTimeout = 2s\n", want: "2s"},
+		{config: "core.lockTimeout = soon\n", want: "0s"},
+		{config: "core.lockTimeout = 2s\nbroken\n", want: "0s"},
+	}
+
+	for _, tt := range tests {
+		v := newTestRepo(t)
+		path := filepath.Join(v.Root(), ".mrvc", "config")
+		if err := os.WriteFile(path, []byte(tt.config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := lockTimeout(v.root).String(); got != tt.want {
+			t.Errorf("lockTimeout with %q = %s, want %s", tt.config, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"path/filepath"
+	"sort"
+)
+
+// FileChange is one path that differs between two snapshots.
+type FileChange struct {
+	Status string // "added", "modified", "deleted", "renamed" or "copied"
+	Path   string
+	Rename *Rename // source of a "renamed" or "copied" path
+}
+
+// NestedRepoChange is one nested repository that differs between two
+// snapshots. Repos are matched by repo_id, so a move shows up as one
+// "moved" change rather than a removal plus an addition.
+type NestedRepoChange struct {
+	Kind    string // "added", "removed", "moved" or "renamed"
+	RepoID  string
+	Name    string
+	OldName string
+	Path    string
+	OldPath string
+}
+
+func (c NestedRepoChange) String() string {
+	name := c.Name
+	if c.OldName != "" && c.OldName != c.Name {
+		name = c.OldName + " → " + c.Name
+	}
+
+	switch c.Kind {
+	case "added":
+		return name + " added at " + c.Path
+	case "removed":
+		return name + " removed from " + c.OldPath
+	case "moved":
+		return name + " moved " + c.OldPath + " → " + c.Path
+	default:
+		return c.OldName + " renamed to " + c.Name + " (" + c.Path + ")"
+	}
+}
+
+// DiffResult lists everything that differs between two snapshots.
+type DiffResult struct {
+	Files       []FileChange
+	NestedRepos []NestedRepoChange
+	Warnings    []string
+}
+
+// ======================================================================
+// DIFF
+// ======================================================================
+
+// Diff compares the snapshot of commit from with the snapshot of commit
+// to. An empty to compares against the working directory instead; only
+// paths tracked by from or to are considered there, like Status does
+// for modified and deleted files.
+func (v *VersionControlV1) Diff(from, to string) (DiffResult, error) {
+	var result DiffResult
+
+	fromFiles, err := commitFiles(v.root, from)
+	if err != nil {
+		return result, err
+	}
+
+	fromNested, err := commitNestedRepos(v.root, from)
+	if err != nil {
+		return result, err
+	}
+
+	var toFiles map[string]string
+	var toNested []model.NestedRepoObject
+
+	if to != "" {
+		if toFiles, err = commitFiles(v.root, to); err != nil {
+			return result, err
+		}
+		if toNested, err = commitNestedRepos(v.root, to); err != nil {
+			return result, err
+		}
+	} else {
+		if toFiles, err = v.workingSnapshot(fromFiles); err != nil {
+			return result, err
+		}
+		if toNested, result.Warnings, err = v.workingNestedRepos(); err != nil {
+			return result, err
+		}
+	}
+
+	fromSide, toSide := v.commitSide(fromFiles), v.commitSide(toFiles)
+	var untracked []string
+
+	// Working directory: untracked files can be rename and copy targets
+	if to == "" {
+		toAll, err := v.hashWorkingFiles()
+		if err != nil {
+			return result, err
+		}
+		for rel := range toAll {
+			if _, tracked := fromFiles[rel]; !tracked {
+				untracked = append(untracked, rel)
+			}
+		}
+		toSide = v.workingSide(toAll)
+	}
+
+	result.Files, err = withRenames(diffFiles(fromFiles, toFiles), fromSide, toSide, untracked)
+	if err != nil {
+		return result, err
+	}
+	result.NestedRepos = diffNestedRepos(fromNested, toNested)
+	return result, nil
+}
+
+// withRenames replaces deleted/added pairs in changes by "renamed"
+// entries and marks added paths copied from another file as "copied".
+// extraAdded are further rename targets not listed in changes (e.g.
+// untracked files); they only appear in the result when matched.
+func withRenames(changes []FileChange, from, to renameSide, extraAdded []string) ([]FileChange, error) {
+	var removed, added, modified []string
+	for _, c := range changes {
+		switch c.Status {
+		case "deleted":
+			removed = append(removed, c.Path)
+		case "added":
+			added = append(added, c.Path)
+		case "modified":
+			modified = append(modified, c.Path)
+		}
+	}
+	added = append(added, extraAdded...)
+
+	renames, err := detectRenames(from, to, removed, added, modified)
+	if err != nil {
+		return nil, err
+	}
+	if len(renames) == 0 {
+		return changes, nil
+	}
+
+	renamedFrom, renamedTo := renamedPaths(renames)
+
+	var out []FileChange
+	for _, c := range changes {
+		if (c.Status == "deleted" && renamedFrom[c.Path]) || (c.Status == "added" && renamedTo[c.Path]) {
+			continue
+		}
+		out = append(out, c)
+	}
+
+	for _, r := range renames {
+		status := "renamed"
+		if r.Copy {
+			status = "copied"
+		}
+		out = append(out, FileChange{Status: status, Path: r.To, Rename: &r})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// diffFiles compares two path → blobHash snapshots, sorted by path.
+func diffFiles(from, to map[string]string) []FileChange {
+	var changes []FileChange
+
+	for path, hash := range from {
+		toHash, exists := to[path]
+		switch {
+		case !exists:
+			changes = append(changes, FileChange{Status: "deleted", Path: path})
+		case toHash != hash:
+			changes = append(changes, FileChange{Status: "modified", Path: path})
+		}
+	}
+
+	for path := range to {
+		if _, exists := from[path]; !exists {
+			changes = append(changes, FileChange{Status: "added", Path: path})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// diffNestedRepos matches nested repos of two snapshots by repo_id
+// (by name for repos without one). When an id appears several times
+// on one side, entries at the same path are paired first.
+func diffNestedRepos(from, to []model.NestedRepoObject) []NestedRepoChange {
+	key := func(n model.NestedRepoObject) string {
+		if n.RepoID != "" {
+			return "id:" + n.RepoID
+		}
+		return "name:" + n.Name
+	}
+
+	fromByKey := make(map[string][]model.NestedRepoObject)
+	for _, n := range from {
+		fromByKey[key(n)] = append(fromByKey[key(n)], n)
+	}
+
+	var changes []NestedRepoChange
+	var added []model.NestedRepoObject
+
+	// Pair identical paths first so duplicated ids do not look moved
+	var unmatched []model.NestedRepoObject
+	for _, n := range to {
+		candidates := fromByKey[key(n)]
+		idx := -1
+		for i, c := range candidates {
+			if c.Path == n.Path {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			unmatched = append(unmatched, n)
+			continue
+		}
+
+		old := candidates[idx]
+		fromByKey[key(n)] = append(candidates[:idx:idx], candidates[idx+1:]...)
+
+		if old.Name != n.Name {
+			changes = append(changes, NestedRepoChange{
+				Kind: "renamed", RepoID: n.RepoID,
+				Name: n.Name, OldName: old.Name,
+				Path: n.Path, OldPath: old.Path,
+			})
+		}
+	}
+
+	for _, n := range unmatched {
+		candidates := fromByKey[key(n)]
+		if len(candidates) == 0 {
+			added = append(added, n)
+			continue
+		}
+
+		old := candidates[0]
+		fromByKey[key(n)] = candidates[1:]
+
+		changes = append(changes, NestedRepoChange{
+			Kind: "moved", RepoID: n.RepoID,
+			Name: n.Name, OldName: old.Name,
+			Path: n.Path, OldPath: old.Path,
+		})
+	}
+
+	for _, n := range added {
+		changes = append(changes, NestedRepoChange{
+			Kind: "added", RepoID: n.RepoID, Name: n.Name, Path: n.Path,
+		})
+	}
+
+	for _, n := range from {
+		for _, left := range fromByKey[key(n)] {
+			if left == n {
+				changes = append(changes, NestedRepoChange{
+					Kind: "removed", RepoID: n.RepoID, Name: n.Name, OldPath: n.Path,
+				})
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changePath(changes[i]) < changePath(changes[j])
+	})
+	return changes
+}
+
+func changePath(c NestedRepoChange) string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return c.OldPath
+}
+
+// commitNestedRepos loads the nested repo pointers recorded in a commit.
+func commitNestedRepos(repoRoot, commitHash string) ([]model.NestedRepoObject, error) {
+	if commitHash == "" {
+		return nil, nil
+	}
+
+	commit, err := readCommit(repoRoot, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	nested := make([]model.NestedRepoObject, 0, len(commit.NestedRepos))
+	for _, h := range commit.NestedRepos {
+		n, err := readNestedRepo(repoRoot, h)
+		if err != nil {
+			return nil, err
+		}
+		nested = append(nested, n)
+	}
+	return nested, nil
+}
+
+// workingNestedRepos describes the repos currently nested in the working
+// directory, plus warnings for repo_ids claimed by several of them.
+func (v *VersionControlV1) workingNestedRepos() ([]model.NestedRepoObject, []string, error) {
+	repos, err := NestedRepos(v.root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nested := make([]model.NestedRepoObject, 0, len(repos))
+	for _, r := range repos {
+		nested = append(nested, model.NestedRepoObject{
+			RepoID: r.Metadata.RepoID,
+			Name:   r.Metadata.Name,
+			Path:   r.RelPath,
+		})
+	}
+
+	// Duplicates anywhere below this repo break identity, not only
+	// among its direct children.
+	all, err := DiscoverRepos(v.root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nested, DuplicateRepoIDs(all), nil
+}
+
+// workingFiles lists the files of the working directory the way commit
+// "*" would see them, as slash-separated relative path → absolute path.
+func (v *VersionControlV1) workingFiles() (map[string]string, error) {
+	files, err := fs.ListFiles(v.root, fs.WalkOptions{
+		IgnoreMRVC:          true,
+		IgnoreNestedRepos:   true,
+		ApplyIgnorePatterns: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(files))
+	for _, f := range files {
+		abs := fs.NormalizePath(f)
+		rel, err := filepath.Rel(v.root, abs)
+		if err != nil {
+			return nil, err
+		}
+		out[filepath.ToSlash(rel)] = abs
+	}
+	return out, nil
+}
+
+// hashWorkingFiles hashes every file of the working directory (minus
+// ignored files and nested repos), path → blob hash.
+func (v *VersionControlV1) hashWorkingFiles() (map[string]string, error) {
+	working, err := v.workingFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]string, len(working))
+	for rel, abs := range working {
+		if hashes[rel], err = fs.CalculateFileHash(abs); err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+// workingSnapshot hashes the working copies of the tracked paths.
+// Paths missing from the working directory are left out.
+func (v *VersionControlV1) workingSnapshot(tracked map[string]string) (map[string]string, error) {
+	working, err := v.workingFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(map[string]string)
+	for rel := range tracked {
+		abs, exists := working[rel]
+		if !exists {
+			continue
+		}
+
+		hash, err := fs.CalculateFileHash(abs)
+		if err != nil {
+			return nil, err
+		}
+		snapshot[rel] = hash
+	}
+	return snapshot, nil
+}
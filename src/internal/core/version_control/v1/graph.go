@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ======================================================================
+// HISTORY GRAPH
+//
+// `log --all` walks every ref at once (HEAD, refs/stash, refs/heads/*,
+// refs/tags/*). Histories that share ancestors are listed in topological
+// order: a commit always comes after all of its children, and a line of
+// history is listed without interruption until it reaches a commit that
+// still has unlisted children, so every branch stays contiguous.
+// ======================================================================
+
+// Ref is a named pointer to a commit.
+type Ref struct {
+	Name string // "HEAD" or a path below .mrvc such as "refs/stash"
+	Hash string
+}
+
+// Decoration returns how ref is shown next to its commit: "HEAD",
+// "main" for refs/heads/main, "tag: v1" for refs/tags/v1 and "stash"
+// for refs/stash.
+func (r Ref) Decoration() string {
+	switch {
+	case strings.HasPrefix(r.Name, "refs/heads/"):
+		return strings.TrimPrefix(r.Name, "refs/heads/")
+	case strings.HasPrefix(r.Name, "refs/tags/"):
+		return "tag: " + strings.TrimPrefix(r.Name, "refs/tags/")
+	}
+	return strings.TrimPrefix(r.Name, "refs/")
+}
+
+// Refs returns HEAD followed by every ref below .mrvc/refs, sorted by
+// name. Refs that point nowhere are left out.
+func (v *VersionControlV1) Refs() ([]Ref, error) {
+	var refs []Ref
+	if head := readHEAD(v.root); head != "" {
+		refs = append(refs, Ref{Name: "HEAD", Hash: head})
+	}
+
+	dir := filepath.Join(v.root, ".mrvc", "refs")
+	var others []Ref
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir && errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".lock") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(filepath.Join(v.root, ".mrvc"), path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		if hash := readRef(v.root, name); hash != "" {
+			others = append(others, Ref{Name: name, Hash: hash})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(others, func(i, j int) bool { return others[i].Name < others[j].Name })
+	return append(refs, others...), nil
+}
+
+// TopoLog returns the commits reachable from starts that pass filter, in
+// topological order (see above). Tips without children are listed newest
+// first, ties broken by their position in starts.
+func (v *VersionControlV1) TopoLog(starts []string, filter LogFilter) ([]LogEntry, error) {
+	entries := make(map[string]LogEntry)
+	children := make(map[string]int)
+	rank := make(map[string]int)
+
+	queue := append([]string(nil), starts...)
+	for i, s := range starts {
+		if _, ok := rank[s]; !ok {
+			rank[s] = i
+		}
+	}
+
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+
+		if _, seen := entries[hash]; seen || hash == "" {
+			continue
+		}
+
+		commit, err := readCommit(v.root, hash)
+		if err != nil {
+			return nil, err
+		}
+		entries[hash] = LogEntry{Hash: hash, Commit: commit}
+
+		if commit.Parent != "" {
+			children[commit.Parent]++
+			queue = append(queue, commit.Parent)
+		}
+	}
+
+	var tips []LogEntry
+	for hash, e := range entries {
+		if children[hash] == 0 {
+			tips = append(tips, e)
+		}
+	}
+	sort.Slice(tips, func(i, j int) bool {
+		if tips[i].Time() != tips[j].Time() {
+			return tips[i].Time() > tips[j].Time()
+		}
+		ri, iok := rank[tips[i].Hash]
+		rj, jok := rank[tips[j].Hash]
+		if iok != jok {
+			return iok
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return tips[i].Hash < tips[j].Hash
+	})
+
+	// A stack instead of a queue: once a commit's last child is listed,
+	// it is listed next, continuing the current line of history.
+	stack := make([]LogEntry, 0, len(tips))
+	for i := len(tips) - 1; i >= 0; i-- {
+		stack = append(stack, tips[i])
+	}
+
+	ordered := make([]LogEntry, 0, len(entries))
+	for len(stack) > 0 {
+		if filter.MaxCount > 0 && len(ordered) >= filter.MaxCount {
+			break
+		}
+
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if filter.Matches(e) {
+			ordered = append(ordered, e)
+		}
+
+		if parent := e.Commit.Parent; parent != "" {
+			children[parent]--
+			if children[parent] == 0 {
+				stack = append(stack, entries[parent])
+			}
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ======================================================================
+// GREP
+//
+// Grep searches the content of tracked files, either as stored in a
+// commit's tree or as they are in the working directory. Working
+// directory searches only look at files HEAD tracks, which excludes
+// ignored files and files of nested repos. Files are searched in
+// parallel; results come back in path order.
+// ======================================================================
+
+// GrepFile holds the matches in one file.
+type GrepFile struct {
+	Path    string // relative to the repository root
+	Binary  bool   // binary file that matches; Matches stays empty
+	Matches []GrepMatch
+}
+
+// GrepMatch is one matching line.
+type GrepMatch struct {
+	Line int // 1-based
+	Text string
+}
+
+// Grep searches the files selected by paths (all when empty) for re.
+// An empty commitHash searches the working directory.
+func (v *VersionControlV1) Grep(re *regexp.Regexp, commitHash string, paths []string) ([]GrepFile, error) {
+	files, err := v.grepSources(commitHash, v.pathFilter(paths))
+	if err != nil {
+		return nil, err
+	}
+
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	results := make([]GrepFile, len(rels))
+	errs := make([]error, len(rels))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				content, err := files[rels[i]]()
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i] = grepContent(re, rels[i], content)
+			}
+		}()
+	}
+
+	for i := range rels {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var matched []GrepFile
+	for i, r := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if r.Binary || len(r.Matches) > 0 {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+// grepSources maps every selected path to a loader of its content.
+func (v *VersionControlV1) grepSources(commitHash string, selected func(string) bool) (map[string]func() ([]byte, error), error) {
+	sources := make(map[string]func() ([]byte, error))
+
+	if commitHash != "" {
+		files, err := commitFiles(v.root, commitHash)
+		if err != nil {
+			return nil, err
+		}
+		for rel, blob := range files {
+			if selected(rel) {
+				sources[rel] = func() ([]byte, error) { return readObject(v.root, blob) }
+			}
+		}
+		return sources, nil
+	}
+
+	tracked, err := commitFiles(v.root, readHEAD(v.root))
+	if err != nil {
+		return nil, err
+	}
+	working, err := v.workingFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	for rel, abs := range working {
+		if _, ok := tracked[rel]; ok && selected(rel) {
+			sources[rel] = func() ([]byte, error) { return os.ReadFile(abs) }
+		}
+	}
+	return sources, nil
+}
+
+// grepContent collects the lines of content matching re. Content with a
+// NUL byte is treated as binary and only reported as a whole.
+func grepContent(re *regexp.Regexp, rel string, content []byte) GrepFile {
+	result := GrepFile{Path: rel}
+
+	if bytes.IndexByte(content, 0) >= 0 {
+		result.Binary = re.Match(content)
+		return result
+	}
+
+	if len(content) == 0 {
+		return result
+	}
+
+	content = bytes.TrimSuffix(content, []byte("\n"))
+	for i, line := range bytes.Split(content, []byte("\n")) {
+		if re.Match(line) {
+			result.Matches = append(result.Matches, GrepMatch{
+				Line: i + 1,
+				Text: string(bytes.TrimSuffix(line, []byte("\r"))),
+			})
+		}
+	}
+	return result
+}
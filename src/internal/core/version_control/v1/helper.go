@@ -2,6 +2,7 @@ package v1
 
 import (
 	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -18,20 +19,28 @@ import (
 //	.mrvc/objects/<first2>/<rest>
 //
 // This keeps directories small and lookup fast.
-func SaveObject(hash string, content []byte) error {
+//
+// Objects are content-addressed, so an existing object is never
+// rewritten. New objects are written atomically: a crash leaves either
+// no object or the complete one.
+func SaveObject(repoRoot, hash string, content []byte) error {
 	if len(hash) < 3 {
 		return errors.New("invalid hash length")
 	}
 
 	// directory split improves filesystem scalability
-	dir := filepath.Join(".mrvc", "objects", hash[:2])
+	dir := filepath.Join(repoRoot, ".mrvc", "objects", hash[:2])
 	file := filepath.Join(dir, hash[2:])
 
+	if fs.FileExists(file) {
+		return nil
+	}
+
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	return os.WriteFile(file, content, 0644)
+	return fs.WriteFileAtomic(file, content, 0644)
 }
 
 // HashContent HASH HELPERS
@@ -39,6 +48,7 @@ func SaveObject(hash string, content []byte) error {
 // HashContent hashes raw bytes.
 // HashTree hash the JSON representation of a directory tree.
 // HashCommit hash the JSON representation of a commit.
+// HashNestedRepo hash the JSON representation of a nested repo pointer.
 // This mirrors Git's core behavior.
 func HashContent(data []byte) string {
 	h := sha256.Sum256(data)
@@ -63,6 +73,15 @@ func HashCommit(commit model.CommitObject) (string, []byte, error) {
 	return hex.EncodeToString(h[:]), jsonBytes, nil
 }
 
+func HashNestedRepo(nested model.NestedRepoObject) (string, []byte, error) {
+	jsonBytes, err := json.Marshal(nested)
+	if err != nil {
+		return "", nil, err
+	}
+	h := sha256.Sum256(jsonBytes)
+	return hex.EncodeToString(h[:]), jsonBytes, nil
+}
+
 // TREE HELPERS
 // addOrReplaceTreeEntry ensures no duplicate directory or file entries
 // exist inside a tree. If an entry already exists, it updates it.
@@ -79,17 +98,14 @@ func addOrReplaceTreeEntry(tree model.TreeObject, entry model.TreeEntry) model.T
 
 // HEAD HELPERS
 // readHEAD returns the current commit hash (or empty if no commits)
-// updateHEAD moves HEAD to a new commit
-func readHEAD() string {
-	data, err := os.ReadFile(".mrvc/HEAD")
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(data))
+// updateHEAD moves HEAD from oldHash to a new commit, failing if another
+// process moved it in the meantime, and logs why (see updateRef)
+func readHEAD(repoRoot string) string {
+	return readRef(repoRoot, "HEAD")
 }
 
-func updateHEAD(hash string) error {
-	return os.WriteFile(".mrvc/HEAD", []byte(strings.TrimSpace(hash)), 0644)
+func updateHEAD(repoRoot, oldHash, newHash, author, reason string) error {
+	return updateRef(repoRoot, "HEAD", oldHash, newHash, author, reason)
 }
 
 // Recursively flattens a TreeObject into path → blobHash mapping
@@ -106,17 +122,11 @@ func flattenTree(repoRoot, prefix string, tree model.TreeObject, out map[string]
 		}
 
 		if entry.EntryType == "tree" {
-			path := filepath.Join(".mrvc", "objects", entry.Hash[:2], entry.Hash[2:])
-			data, err := os.ReadFile(path)
+			subtree, err := readTree(repoRoot, entry.Hash)
 			if err != nil {
 				return err
 			}
 
-			var subtree model.TreeObject
-			if err := json.Unmarshal(data, &subtree); err != nil {
-				return err
-			}
-
 			if err := flattenTree(repoRoot, full, subtree, out); err != nil {
 				return err
 			}
@@ -124,3 +134,139 @@ func flattenTree(repoRoot, prefix string, tree model.TreeObject, out map[string]
 	}
 	return nil
 }
+
+// OBJECT READ HELPERS
+// objectPath returns where an object lives inside repoRoot's store.
+// readCommit / readTree / readNestedRepo load and decode a stored object.
+func objectPath(repoRoot, hash string) string {
+	return filepath.Join(repoRoot, ".mrvc", "objects", hash[:2], hash[2:])
+}
+
+// CorruptError reports an object that is missing from the store, does
+// not match its hash or cannot be decoded: the repository is damaged.
+type CorruptError struct {
+	Hash string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	return "corrupt object " + e.Hash + ": " + e.Err.Error()
+}
+
+func (e *CorruptError) Unwrap() error { return e.Err }
+
+// readObject returns the content of an object after checking it against
+// its hash. Only a missing object or a hash mismatch is corruption; other
+// read errors are returned as they are.
+func readObject(repoRoot, hash string) ([]byte, error) {
+	if len(hash) < 3 {
+		return nil, errors.New("invalid object hash: " + hash)
+	}
+
+	data, err := os.ReadFile(objectPath(repoRoot, hash))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &CorruptError{Hash: hash, Err: errors.New("object missing")}
+	}
+	if err != nil {
+		return nil, err // e.g. permissions: the object may well be intact
+	}
+	if HashContent(data) != hash {
+		return nil, &CorruptError{Hash: hash, Err: errors.New("content does not match its hash")}
+	}
+	return data, nil
+}
+
+func readCommit(repoRoot, hash string) (model.CommitObject, error) {
+	var commit model.CommitObject
+
+	data, err := readObject(repoRoot, hash)
+	if err != nil {
+		return commit, err
+	}
+
+	if err := json.Unmarshal(data, &commit); err != nil {
+		return commit, &CorruptError{Hash: hash, Err: err}
+	}
+
+	// Older commits only carry an author name and the commit time.
+	if commit.Author.Timestamp == "" {
+		commit.Author.Timestamp = commit.Timestamp
+	}
+	if commit.Committer.Name == "" {
+		commit.Committer = commit.Author
+	}
+	return commit, nil
+}
+
+func readTree(repoRoot, hash string) (model.TreeObject, error) {
+	var tree model.TreeObject
+
+	data, err := readObject(repoRoot, hash)
+	if err != nil {
+		return tree, err
+	}
+
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return tree, &CorruptError{Hash: hash, Err: err}
+	}
+	return tree, nil
+}
+
+func readNestedRepo(repoRoot, hash string) (model.NestedRepoObject, error) {
+	var nested model.NestedRepoObject
+
+	data, err := readObject(repoRoot, hash)
+	if err != nil {
+		return nested, err
+	}
+
+	if err := json.Unmarshal(data, &nested); err != nil {
+		return nested, &CorruptError{Hash: hash, Err: err}
+	}
+	return nested, nil
+}
+
+// commitFiles returns the path → blobHash snapshot of a commit.
+// An empty hash (no commits yet) yields an empty snapshot.
+func commitFiles(repoRoot, commitHash string) (map[string]string, error) {
+	files := make(map[string]string)
+	if commitHash == "" {
+		return files, nil
+	}
+
+	commit, err := readCommit(repoRoot, commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// A commit without files has no root tree
+	if commit.Tree == "" {
+		return files, nil
+	}
+
+	tree, err := readTree(repoRoot, commit.Tree)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := flattenTree(repoRoot, "", tree, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// ShortHash abbreviates a hash for display.
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// subject returns the first line of a commit message.
+func subject(message string) string {
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return message[:i]
+	}
+	return message
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RepoInfo describes one repository inside a nested repo hierarchy.
+type RepoInfo struct {
+	Metadata model.Metadata
+	Path     string // absolute, slash-normalized repo root
+	RelPath  string // path relative to the hierarchy root ("." for the root itself)
+}
+
+// ReadMetadata loads .mrvc/metadata.json of the repository at repoRoot.
+func ReadMetadata(repoRoot string) (model.Metadata, error) {
+	var meta model.Metadata
+	err := fs.ReadJSON(filepath.Join(repoRoot, ".mrvc", "metadata.json"), &meta)
+	return meta, err
+}
+
+// DiscoverRepos returns the repository at root followed by every nested
+// repository below it, at any depth, in lexical path order.
+//
+// A nested repository is any directory containing its own .mrvc folder
+// (see docs/v1/NestedRepo.md). .mrvc folders themselves are never entered.
+func DiscoverRepos(root string) ([]RepoInfo, error) {
+	root = fs.NormalizePath(root)
+
+	if !fs.IsDirPresent(filepath.Join(root, ".mrvc")) {
+		return nil, errors.New("not an mrvc repository: " + root)
+	}
+
+	return walkRepos(root, true, true)
+}
+
+// NestedRepos returns the repositories directly nested in root. Repos
+// nested inside those belong to them and are not included.
+func NestedRepos(root string) ([]RepoInfo, error) {
+	return walkRepos(fs.NormalizePath(root), false, false)
+}
+
+// walkRepos collects every directory below root holding a .mrvc folder.
+// includeRoot adds root itself; recursive descends into found repos.
+func walkRepos(root string, includeRoot, recursive bool) ([]RepoInfo, error) {
+	var repos []RepoInfo
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+
+		if info.Name() == ".mrvc" {
+			return filepath.SkipDir
+		}
+
+		isRoot := fs.NormalizePath(path) == root
+		if (isRoot && !includeRoot) || !fs.IsDirPresent(filepath.Join(path, ".mrvc")) {
+			return nil
+		}
+
+		meta, err := ReadMetadata(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		repos = append(repos, RepoInfo{
+			Metadata: meta,
+			Path:     fs.NormalizePath(path),
+			RelPath:  filepath.ToSlash(rel),
+		})
+
+		if !isRoot && !recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	return repos, err
+}
+
+// FindRepo resolves a repository by name or repo_id within repos.
+func FindRepo(repos []RepoInfo, ref string) (RepoInfo, error) {
+	for _, r := range repos {
+		if r.Metadata.RepoID != "" && r.Metadata.RepoID == ref {
+			return r, nil
+		}
+	}
+
+	var matches []RepoInfo
+	for _, r := range repos {
+		if r.Metadata.Name == ref {
+			matches = append(matches, r)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return RepoInfo{}, errors.New("no repository named or identified by: " + ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return RepoInfo{}, errors.New("repository name is ambiguous, use its repo_id: " + ref)
+	}
+}
+
+// DuplicateRepoIDs reports every repo_id claimed by more than one
+// directory, usually the result of copying a .mrvc folder. Such copies
+// break the identity guarantee that a repo_id names exactly one repo.
+func DuplicateRepoIDs(repos []RepoInfo) []string {
+	paths := make(map[string][]string)
+	var order []string
+
+	for _, r := range repos {
+		id := r.Metadata.RepoID
+		if id == "" {
+			continue
+		}
+		if _, seen := paths[id]; !seen {
+			order = append(order, id)
+		}
+		paths[id] = append(paths[id], r.RelPath)
+	}
+
+	var warnings []string
+	for _, id := range order {
+		if len(paths[id]) > 1 {
+			warnings = append(warnings, "repo_id "+id+" is claimed by several directories: "+strings.Join(paths[id], ", "))
+		}
+	}
+	return warnings
+}
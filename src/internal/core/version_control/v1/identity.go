@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ======================================================================
+// IDENTITY
+//
+// Every commit records an author (who wrote the change) and a committer
+// (who recorded it). They differ when a commit is replayed by
+// cherry-pick or rebase, which keep the original author. The current
+// user is taken from MRVC_AUTHOR_NAME and MRVC_AUTHOR_EMAIL, falling back
+// to user.name and user.email from the config (see config.go).
+// ======================================================================
+
+const (
+	envAuthorName  = "MRVC_AUTHOR_NAME"
+	envAuthorEmail = "MRVC_AUTHOR_EMAIL"
+)
+
+// ErrNoIdentity is returned when a commit needs an author but none is
+// configured.
+var ErrNoIdentity = errors.New("author identity unknown: run `mrvc config set --global user.name <name>` " +
+	"and user.email, set " + envAuthorName + " and " + envAuthorEmail +
+	", pass --author \"Name <email>\" or use --allow-anonymous")
+
+// AnonymousName is the author of commits made with --allow-anonymous.
+const AnonymousName = "unknown"
+
+// NewIdentity returns an identity acting now, in the local time zone.
+func NewIdentity(name, email string) model.Identity {
+	now := time.Now()
+	_, offset := now.Zone()
+
+	return model.Identity{
+		Name:      name,
+		Email:     email,
+		Timestamp: strconv.FormatInt(now.UnixMilli(), 10),
+		TZOffset:  offset / 60,
+	}
+}
+
+// ParseIdentity parses "Name <email>" or a bare name into an identity
+// acting now.
+func ParseIdentity(s string) model.Identity {
+	s = strings.TrimSpace(s)
+
+	open := strings.LastIndex(s, "<")
+	if open < 0 || !strings.HasSuffix(s, ">") {
+		return NewIdentity(s, "")
+	}
+	return NewIdentity(strings.TrimSpace(s[:open]), strings.TrimSpace(s[open+1:len(s)-1]))
+}
+
+// Identity returns the current user, acting now, or ErrNoIdentity. The
+// config is only read for what the environment leaves out, so a broken
+// config is an error only when the name has to come from it.
+func (v *VersionControlV1) Identity() (model.Identity, error) {
+	name := strings.TrimSpace(os.Getenv(envAuthorName))
+	email := strings.TrimSpace(os.Getenv(envAuthorEmail))
+
+	if name == "" || email == "" {
+		config, err := v.Config()
+		if err != nil && name == "" {
+			return model.Identity{}, err
+		}
+		if err == nil {
+			if name == "" {
+				name = config.Value("user.name", "")
+			}
+			if email == "" {
+				email = config.Value("user.email", "")
+			}
+		}
+	}
+
+	if name == "" {
+		return model.Identity{}, ErrNoIdentity
+	}
+	return NewIdentity(name, email), nil
+}
+
+// refMover is who the reflog records for ref moves that write no commit
+// (reset, checkout, abort): the current user, or else the repository
+// author from metadata.json. Writing a commit always needs Identity.
+func (v *VersionControlV1) refMover() model.Identity {
+	if id, err := v.Identity(); err == nil {
+		return id
+	}
+
+	meta, err := ReadMetadata(v.root)
+	if err != nil || meta.Author == "" {
+		return NewIdentity(AnonymousName, "")
+	}
+	return NewIdentity(meta.Author, "")
+}
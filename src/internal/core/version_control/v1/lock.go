@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ======================================================================
+// REPOSITORY LOCK
+//
+// Every mutating operation holds .mrvc/repo.lock for its whole duration,
+// so two mrvc processes in the same checkout (e.g. parallel CI jobs)
+// cannot interleave. The lock file records the owner's PID and host.
+//
+// A lock is stale when its owner ran on this host and is no longer
+// alive (crash, kill -9); stale locks are taken over automatically.
+// Locks from other hosts are always honored since their PIDs cannot be
+// checked from here.
+//
+// With core.lockTimeout set (e.g. "30s"), a held lock is waited for up
+// to that long instead of failing right away.
+// ======================================================================
+
+// ErrLocked is returned when another live mrvc process holds the lock.
+var ErrLocked = errors.New("another mrvc process is running in this repository")
+
+// lockFileName lives inside .mrvc.
+const lockFileName = "repo.lock"
+
+// unreadableLockAge is how old a lock file without a readable owner must
+// be before it is considered abandoned (its writer died mid-write).
+const unreadableLockAge = 10 * time.Second
+
+// lockRetryInterval is how often a held lock is retried while waiting
+// for core.lockTimeout.
+const lockRetryInterval = 100 * time.Millisecond
+
+// lock acquires the repository lock. The returned function releases it.
+func (v *VersionControlV1) lock() (func(), error) {
+	return lockRepo(v.root)
+}
+
+// lockTimeout is core.lockTimeout, or 0 when the config cannot be read
+// or the value is invalid. A broken config must not lock users out of
+// every command, including the `mrvc config` that would fix it; the CLI
+// warns about it on its own.
+func lockTimeout(repoRoot string) time.Duration {
+	config, err := LoadConfig(repoRoot)
+	if err != nil {
+		return 0
+	}
+	timeout, err := config.Duration("core.lockTimeout", 0)
+	if err != nil {
+		return 0
+	}
+	return timeout
+}
+
+func lockRepo(repoRoot string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout(repoRoot))
+	for {
+		unlock, err := tryLockRepo(repoRoot)
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return unlock, err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// tryLockRepo takes the lock if it is free or stale.
+func tryLockRepo(repoRoot string) (func(), error) {
+	path := filepath.Join(repoRoot, ".mrvc", lockFileName)
+
+	// Second attempt only happens after removing a stale lock
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, werr := fmt.Fprintf(f, "%d\n%s\n", os.Getpid(), hostname())
+			cerr := f.Close()
+			if werr != nil || cerr != nil {
+				os.Remove(path)
+				return nil, errors.Join(werr, cerr)
+			}
+			return func() { os.Remove(path) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		content, pid, stale := inspectLock(path)
+		if !stale {
+			if pid > 0 {
+				return nil, fmt.Errorf("%w (pid %d, lock file %s)", ErrLocked, pid, path)
+			}
+			return nil, fmt.Errorf("%w (lock file %s)", ErrLocked, path)
+		}
+
+		// Only remove the lock if nobody replaced it in the meantime
+		if current, _ := os.ReadFile(path); string(current) == content {
+			os.Remove(path)
+		}
+	}
+
+	return nil, fmt.Errorf("%w (lock file %s)", ErrLocked, path)
+}
+
+// inspectLock reads a lock file and decides whether its owner is gone.
+func inspectLock(path string) (content string, pid int, stale bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// Vanished between our create attempt and now → just retry
+		return "", 0, os.IsNotExist(err)
+	}
+	content = string(data)
+
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	pid, perr := strconv.Atoi(strings.TrimSpace(lines[0]))
+
+	if perr != nil || len(lines) < 2 {
+		info, err := os.Stat(path)
+		return content, 0, err == nil && time.Since(info.ModTime()) > unreadableLockAge
+	}
+
+	if strings.TrimSpace(lines[1]) != hostname() {
+		return content, pid, false
+	}
+
+	return content, pid, !processAlive(pid)
+}
+
+func hostname() string {
+	h, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return h
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package v1
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with the given PID exists.
+// Signal 0 performs the existence and permission checks only.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package v1
+
+import "os"
+
+// processAlive reports whether a process with the given PID exists.
+// On Windows FindProcess opens a handle and fails for unknown PIDs.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
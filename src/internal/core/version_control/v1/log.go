@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LogEntry is one commit of a history listing.
+type LogEntry struct {
+	Hash   string
+	Commit model.CommitObject
+	Repo   string  // name of the repo the commit belongs to
+	Rename *Rename // FollowLog: the followed path was renamed or copied here
+}
+
+// Time returns the commit timestamp in milliseconds (0 if unparsable).
+func (e LogEntry) Time() int64 {
+	ms, _ := strconv.ParseInt(e.Commit.Timestamp, 10, 64)
+	return ms
+}
+
+// Subject returns the first line of the commit message.
+func (e LogEntry) Subject() string {
+	return subject(e.Commit.Message)
+}
+
+// Body returns the commit message after the subject and the blank lines
+// separating them.
+func (e LogEntry) Body() string {
+	i := strings.IndexByte(e.Commit.Message, '\n')
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimLeft(e.Commit.Message[i+1:], "\n")
+}
+
+// LogFilter selects which commits a log shows. Zero values disable a
+// filter.
+type LogFilter struct {
+	Author   string // substring of the author's "Name <email>"
+	Grep     string // substring of the message
+	Since    int64  // inclusive lower bound, milliseconds
+	Until    int64  // inclusive upper bound, milliseconds
+	MaxCount int    // maximum number of entries
+}
+
+// Matches reports whether e passes every filter except MaxCount.
+func (f LogFilter) Matches(e LogEntry) bool {
+	if f.Author != "" && !strings.Contains(e.Commit.Author.String(), f.Author) {
+		return false
+	}
+	if f.Grep != "" && !strings.Contains(e.Commit.Message, f.Grep) {
+		return false
+	}
+	if f.Since != 0 && e.Time() < f.Since {
+		return false
+	}
+	if f.Until != 0 && e.Time() > f.Until {
+		return false
+	}
+	return true
+}
+
+// ======================================================================
+// LOG
+// ======================================================================
+
+// Log walks the parent chain starting at commit start, newest first,
+// and returns the commits passing filter.
+func (v *VersionControlV1) Log(start string, filter LogFilter) ([]LogEntry, error) {
+	var entries []LogEntry
+
+	for hash := start; hash != ""; {
+		if filter.MaxCount > 0 && len(entries) >= filter.MaxCount {
+			break
+		}
+
+		commit, err := readCommit(v.root, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := LogEntry{Hash: hash, Commit: commit}
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+
+		hash = commit.Parent
+	}
+
+	return entries, nil
+}
+
+// FollowLog is Log restricted to the commits that changed path (relative
+// to the repository root), following the file back through renames and
+// copies. The walk ends at the commit that introduced it.
+func (v *VersionControlV1) FollowLog(start, path string, filter LogFilter) ([]LogEntry, error) {
+	rel, err := v.repoRelPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := commitFiles(v.root, start)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := files[rel]; !ok {
+		return nil, errors.New("no such path '" + rel + "' in " + ShortHash(start))
+	}
+
+	var entries []LogEntry
+
+	for hash := start; hash != ""; {
+		if filter.MaxCount > 0 && len(entries) >= filter.MaxCount {
+			break
+		}
+
+		commit, err := readCommit(v.root, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		parentFiles, err := commitFiles(v.root, commit.Parent)
+		if err != nil {
+			return nil, err
+		}
+
+		blob := files[rel]
+		parentBlob, inParent := parentFiles[rel]
+
+		entry := LogEntry{Hash: hash, Commit: commit}
+		introduced := !inParent
+
+		if introduced {
+			rename, err := v.findRenameSource(parentFiles, files, rel)
+			if err != nil {
+				return nil, err
+			}
+			if rename != nil {
+				entry.Rename = rename
+				rel, introduced = rename.From, false
+			}
+		}
+
+		if (introduced || entry.Rename != nil || parentBlob != blob) && filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+		if introduced {
+			break
+		}
+
+		hash, files = commit.Parent, parentFiles
+	}
+
+	return entries, nil
+}
+
+// findRenameSource looks for the file path was renamed or copied from
+// between the parent and child snapshots. It returns nil when path is
+// new content.
+func (v *VersionControlV1) findRenameSource(parentFiles, files map[string]string, path string) (*Rename, error) {
+	var removed, modified []string
+	for p, h := range parentFiles {
+		switch childHash, ok := files[p]; {
+		case !ok:
+			removed = append(removed, p)
+		case childHash != h:
+			modified = append(modified, p)
+		}
+	}
+
+	renames, err := detectRenames(v.commitSide(parentFiles), v.commitSide(files), removed, []string{path}, modified)
+	if err != nil || len(renames) == 0 {
+		return nil, err
+	}
+	return &renames[0], nil
+}
+
+// RecursiveLog interleaves the histories of several repos into a single
+// timeline, newest first. Each entry is tagged with its repo's name.
+// Repos without commits are skipped.
+func RecursiveLog(repos []RepoInfo, filter LogFilter) ([]LogEntry, error) {
+	var all []LogEntry
+
+	for _, r := range repos {
+		head := readHEAD(r.Path)
+		if head == "" {
+			continue
+		}
+
+		// A repo can contribute at most MaxCount entries to the merged
+		// result, so the per-repo walk may stop there as well.
+		entries, err := NewAt(r.Path).Log(head, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range entries {
+			entries[i].Repo = r.Metadata.Name
+		}
+		all = append(all, entries...)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Time() > all[j].Time()
+	})
+
+	if filter.MaxCount > 0 && len(all) > filter.MaxCount {
+		all = all[:filter.MaxCount]
+	}
+	return all, nil
+}
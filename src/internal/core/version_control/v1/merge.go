@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/utils/diff"
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// ======================================================================
+// THREE-WAY MERGE OF SNAPSHOTS
+//
+// Every path is resolved independently from its base, ours and theirs
+// blob (a missing blob means the file does not exist on that side):
+//   • ours == theirs            → ours
+//   • base == ours              → theirs (theirs changed, possibly deleted)
+//   • base == theirs            → ours
+//   • changed on both sides     → line-based merge (diff3); overlapping
+//                                 edits, binary files and modify/delete
+//                                 pairs are conflicts
+// ======================================================================
+
+// snapshotMerge is the outcome of mergeSnapshots.
+type snapshotMerge struct {
+	files     map[string]string // cleanly merged snapshot, path → blob
+	conflicts map[string]string // conflicted path → blob to put in the working dir
+}
+
+// conflictPaths returns the conflicted paths in order.
+func (m snapshotMerge) conflictPaths() []string {
+	paths := make([]string, 0, len(m.conflicts))
+	for p := range m.conflicts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// workingTarget is what the working directory should hold after the
+// merge: merged files plus conflicted files with markers.
+func (m snapshotMerge) workingTarget() map[string]string {
+	target := make(map[string]string, len(m.files)+len(m.conflicts))
+	for p, h := range m.files {
+		target[p] = h
+	}
+	for p, h := range m.conflicts {
+		target[p] = h
+	}
+	return target
+}
+
+func (v *VersionControlV1) mergeSnapshots(base, ours, theirs map[string]string, oursLabel, theirsLabel string) (snapshotMerge, error) {
+	result := snapshotMerge{
+		files:     make(map[string]string),
+		conflicts: make(map[string]string),
+	}
+
+	paths := make(map[string]bool)
+	for _, snapshot := range []map[string]string{base, ours, theirs} {
+		for p := range snapshot {
+			paths[p] = true
+		}
+	}
+
+	for p := range paths {
+		b, o, t := base[p], ours[p], theirs[p]
+
+		var merged string
+		switch {
+		case o == t:
+			merged = o
+		case b == o:
+			merged = t
+		case b == t:
+			merged = o
+		case o == "" || t == "":
+			// modify/delete: keep the surviving version for resolution
+			result.conflicts[p] = o + t
+			continue
+		default:
+			hash, clean, err := v.mergeBlobs(b, o, t, oursLabel, theirsLabel)
+			if err != nil {
+				return result, err
+			}
+			if !clean {
+				result.conflicts[p] = hash
+				continue
+			}
+			merged = hash
+		}
+
+		if merged != "" {
+			result.files[p] = merged
+		}
+	}
+
+	return result, nil
+}
+
+// mergeBlobs merges file contents line by line and stores the result.
+// Binary files cannot be merged; ours is kept and reported as conflict.
+func (v *VersionControlV1) mergeBlobs(base, ours, theirs, oursLabel, theirsLabel string) (string, bool, error) {
+	contents := make([][]byte, 3)
+	for i, h := range []string{base, ours, theirs} {
+		if h == "" {
+			continue
+		}
+		data, err := readObject(v.root, h)
+		if err != nil {
+			return "", false, err
+		}
+		contents[i] = data
+	}
+
+	for _, c := range contents {
+		if bytes.IndexByte(c, 0) >= 0 {
+			return ours, false, nil
+		}
+	}
+
+	lines, conflicts := diff.Merge3(
+		diff.SplitLines(string(contents[0])),
+		diff.SplitLines(string(contents[1])),
+		diff.SplitLines(string(contents[2])),
+		oursLabel, theirsLabel,
+	)
+
+	merged := []byte(strings.Join(lines, ""))
+	hash := HashContent(merged)
+	if err := SaveObject(v.root, hash, merged); err != nil {
+		return "", false, err
+	}
+
+	return hash, conflicts == 0, nil
+}
@@ -1,9 +1,17 @@
 package model
 
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
 type Metadata struct {
 	Name      string `json:"name"`
 	Author    string `json:"author"`
 	CreatedAt string `json:"created_at"`
+	RepoID    string `json:"repo_id"`
 }
 
 // TREE ---------------------------------------------------------------------
@@ -21,9 +29,112 @@ type TreeEntry struct {
 // COMMIT --------------------------------------------------------------------
 
 type CommitObject struct {
-	Tree      string `json:"tree"`
-	Parent    string `json:"parent"`
-	Message   string `json:"message"`
+	Tree        string   `json:"tree"`
+	Parent      string   `json:"parent"`
+	Message     string   `json:"message"`
+	Author      Identity `json:"author"`                 // who wrote the change
+	Committer   Identity `json:"committer"`              // who recorded it; missing in older commits
+	Timestamp   string   `json:"timestamp"`              // commit time (the committer's timestamp)
+	NestedRepos []string `json:"nested_repos,omitempty"` // NestedRepoObject hashes
+}
+
+// Identity names a person and when they acted. Commits written before
+// identities existed store the author as a plain name string.
+type Identity struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Timestamp string `json:"timestamp"` // milliseconds since the epoch
+	TZOffset  int    `json:"tz_offset"` // minutes east of UTC
+}
+
+// String returns "Name <email>", or just the name without an email.
+func (id Identity) String() string {
+	if id.Email == "" {
+		return id.Name
+	}
+	return fmt.Sprintf("%s <%s>", id.Name, id.Email)
+}
+
+// Time returns when the identity acted, in its own time zone.
+func (id Identity) Time() time.Time {
+	ms, _ := strconv.ParseInt(id.Timestamp, 10, 64)
+	return time.UnixMilli(ms).In(time.FixedZone("", id.TZOffset*60))
+}
+
+// UnmarshalJSON accepts both the identity object and the plain name
+// string of older commits.
+func (id *Identity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*id = Identity{Name: name}
+		return nil
+	}
+
+	type plain Identity // without this method, avoiding recursion
+	return json.Unmarshal(data, (*plain)(id))
+}
+
+// NESTED REPO ---------------------------------------------------------------
+
+// NestedRepoObject points at a nested repository at commit time. Its hash
+// only changes when the nested repo moves, is renamed or is replaced.
+type NestedRepoObject struct {
+	RepoID string `json:"repo_id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"` // relative to the parent repo root
+}
+
+// WORKSPACE -----------------------------------------------------------------
+
+// WorkspaceManifest describes a whole nested repo hierarchy so it can be
+// recreated elsewhere with `mrvc workspace sync`.
+type WorkspaceManifest struct {
+	Version int             `json:"version"`
+	Repos   []WorkspaceRepo `json:"repos"`
+}
+
+type WorkspaceRepo struct {
+	RepoID string `json:"repo_id"`
+	Name   string `json:"name"`
+	Path   string `json:"path"`   // relative to the workspace root, "." for the root repo
+	Commit string `json:"commit"` // pinned commit, empty if the repo has no commits
+	Source string `json:"source"` // repository to clone or update from
+}
+
+// REFLOG --------------------------------------------------------------------
+
+// ReflogEntry records one movement of a ref. Entries are appended as JSON
+// lines to .mrvc/logs/<ref>.
+type ReflogEntry struct {
+	Old       string `json:"old"`
+	New       string `json:"new"`
 	Author    string `json:"author"`
 	Timestamp string `json:"timestamp"`
+	Reason    string `json:"reason"` // e.g. "commit: fix login", "checkout: moving to <hash>"
+}
+
+// SEQUENCER -----------------------------------------------------------------
+
+// SequencerState persists a revert, cherry-pick or rebase that stopped
+// on conflicts, so it can be continued or aborted later.
+type SequencerState struct {
+	Operation    string            `json:"operation"`               // "revert", "cherry-pick" or "rebase"
+	OrigHead     string            `json:"orig_head"`               // HEAD before the operation started
+	Onto         string            `json:"onto,omitempty"`          // rebase: new base of the replayed commits
+	Current      string            `json:"current"`                 // commit whose change is being applied
+	Todo         []string          `json:"todo"`                    // commits still to apply after Current
+	Fixups       []string          `json:"fixups,omitempty"`        // rebase --autosquash: commits folded into their predecessor
+	RecordOrigin bool              `json:"record_origin,omitempty"` // cherry-pick -x
+	Snapshot     map[string]string `json:"snapshot"`                // merged files, path → blob hash
+	Conflicts    []string          `json:"conflicts"`               // paths to take from the working dir
+}
+
+// BISECT --------------------------------------------------------------------
+
+// BisectState persists a bisect session between commands.
+type BisectState struct {
+	OrigHead string   `json:"orig_head"` // HEAD before bisect start, restored by reset
+	Bad      string   `json:"bad"`       // newest known bad commit
+	Good     string   `json:"good"`      // newest known good ancestor of Bad
+	Skipped  []string `json:"skipped"`   // commits that cannot be tested
 }
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"strings"
+)
+
+// ======================================================================
+// REBASE
+//
+// Rebase replays the commits of HEAD's first-parent chain that upstream
+// does not contain onto a new base (upstream itself, or --onto), one by
+// one, keeping authors and messages. Changes already present in the new
+// base are dropped. The state of a stopped rebase lives in
+// .mrvc/rebase-merge (see sequencer.go).
+//
+// With autosquash, a commit whose subject is "fixup! <subject>" is moved
+// right after the commit it names and folded into it, keeping that
+// commit's message.
+// ======================================================================
+
+// fixupPrefix marks a commit for --autosquash.
+const fixupPrefix = "fixup! "
+
+// Rebase replays upstream..HEAD onto onto (upstream when empty).
+// Conflicts stop with a *ConflictError; resolve them and call
+// RebaseContinue, or use RebaseSkip / RebaseAbort.
+func (v *VersionControlV1) Rebase(upstream, onto string, autosquash bool) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := ensureNoSequence(v.root); err != nil {
+		return err
+	}
+
+	head := readHEAD(v.root)
+	if head == "" {
+		return errors.New("cannot rebase an empty repository")
+	}
+	if _, err := v.Identity(); err != nil {
+		return err
+	}
+	if onto == "" {
+		onto = upstream
+	}
+
+	commits, err := v.uniqueCommits(head, upstream)
+	if err != nil {
+		return err
+	}
+
+	var fixups []string
+	if autosquash {
+		commits, fixups, err = v.autosquash(commits)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Already based on onto with nothing to fold
+	forkPoint := head
+	if len(commits) > 0 {
+		first, err := readCommit(v.root, commits[0])
+		if err != nil {
+			return err
+		}
+		forkPoint = first.Parent
+	}
+	if forkPoint == onto && len(fixups) == 0 {
+		return nil
+	}
+
+	if len(commits) == 0 {
+		return v.checkoutCommit(onto, false, "rebase: checkout "+ShortHash(onto))
+	}
+
+	state := newSequence("rebase", head, commits)
+	state.Onto = onto
+	state.Fixups = fixups
+
+	// Saved before HEAD leaves head, so --abort can always return to it
+	if err := fs.WriteJSON(sequencerPath(v.root, state.Operation), state); err != nil {
+		return err
+	}
+	if err := v.checkoutCommit(onto, false, "rebase: checkout "+ShortHash(onto)); err != nil {
+		if cerr := clearSequencer(v.root, state.Operation); cerr != nil {
+			return cerr
+		}
+		return err
+	}
+
+	return v.runSequence(state, v.rebasePick)
+}
+
+// RebaseContinue commits the resolved commit and replays the rest.
+func (v *VersionControlV1) RebaseContinue() error {
+	return v.continueOperation("rebase", v.rebasePick)
+}
+
+// RebaseSkip drops the conflicted commit and replays the rest.
+func (v *VersionControlV1) RebaseSkip() error {
+	return v.skipOperation("rebase", v.rebasePick)
+}
+
+// RebaseAbort restores HEAD and the working files to before the rebase.
+func (v *VersionControlV1) RebaseAbort() error {
+	return v.abortOperation("rebase")
+}
+
+// rebasePick builds the change parent → commit. A fixup replaces HEAD,
+// keeping its message and author.
+func (v *VersionControlV1) rebasePick(state model.SequencerState, commitHash string) (pick, error) {
+	commit, err := readCommit(v.root, commitHash)
+	if err != nil {
+		return pick{}, err
+	}
+
+	p := pick{
+		Commit:    commitHash,
+		Base:      commit.Parent,
+		Theirs:    commitHash,
+		Label:     ShortHash(commitHash) + " (" + subject(commit.Message) + ")",
+		Message:   commit.Message,
+		Author:    commit.Author,
+		DropEmpty: true,
+	}
+
+	for _, f := range state.Fixups {
+		if f != commitHash {
+			continue
+		}
+
+		head, err := readCommit(v.root, readHEAD(v.root))
+		if err != nil {
+			return pick{}, err
+		}
+		p.Message, p.Author, p.Amend = head.Message, head.Author, true
+		break
+	}
+
+	return p, nil
+}
+
+// uniqueCommits lists the commits of head's first-parent chain that are
+// not ancestors of upstream, oldest first.
+func (v *VersionControlV1) uniqueCommits(head, upstream string) ([]string, error) {
+	upstreamChain := make(map[string]bool)
+	for h := upstream; h != ""; {
+		upstreamChain[h] = true
+
+		commit, err := readCommit(v.root, h)
+		if err != nil {
+			return nil, err
+		}
+		h = commit.Parent
+	}
+
+	var commits []string
+	for h := head; h != "" && !upstreamChain[h]; {
+		commits = append(commits, h)
+
+		commit, err := readCommit(v.root, h)
+		if err != nil {
+			return nil, err
+		}
+		h = commit.Parent
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, nil
+}
+
+// autosquash moves every "fixup! <subject>" commit right after the latest
+// earlier commit it names (by subject, subject prefix or hash prefix) and
+// returns the new order plus the commits to fold. Fixups naming no commit
+// of the range stay ordinary picks.
+func (v *VersionControlV1) autosquash(commits []string) (ordered, fixups []string, err error) {
+	// groups[i] is a commit followed by the fixups folded into it
+	var groups [][]string
+	var subjects []string
+
+	for _, hash := range commits {
+		commit, err := readCommit(v.root, hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		subj := subject(commit.Message)
+
+		target := -1
+		if strings.HasPrefix(subj, fixupPrefix) {
+			name := subj
+			for strings.HasPrefix(name, fixupPrefix) {
+				name = strings.TrimPrefix(name, fixupPrefix)
+			}
+			target = findFixupTarget(groups, subjects, name)
+		}
+
+		if target >= 0 {
+			groups[target] = append(groups[target], hash)
+			fixups = append(fixups, hash)
+			continue
+		}
+
+		groups = append(groups, []string{hash})
+		subjects = append(subjects, subj)
+	}
+
+	for _, g := range groups {
+		ordered = append(ordered, g...)
+	}
+	return ordered, fixups, nil
+}
+
+// findFixupTarget returns the latest group whose commit matches name, or -1.
+func findFixupTarget(groups [][]string, subjects []string, name string) int {
+	for i := len(groups) - 1; i >= 0; i-- {
+		if subjects[i] == name {
+			return i
+		}
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		if strings.HasPrefix(subjects[i], name) {
+			return i
+		}
+		if len(name) >= minPrefix && strings.HasPrefix(groups[i][0], name) {
+			return i
+		}
+	}
+	return -1
+}
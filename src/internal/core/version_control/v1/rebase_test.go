@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// stoppedRebase rebases three commits (add a.txt, add b.txt, delete
+// b.txt) onto a commit changing file.txt while an untracked b.txt is in
+// the way, so the second pick fails after HEAD left the branch. It
+// returns the branch tip the rebase started from.
+func stoppedRebase(t *testing.T, v *VersionControlV1) string {
+	t.Helper()
+
+	c1 := commitSnapshot(t, v, "one", map[string]string{"file.txt": "one"})
+	upstream := commitSnapshot(t, v, "two", map[string]string{"file.txt": "two"})
+
+	if err := v.Reset(c1, "HEAD~1", ResetHard); err != nil {
+		t.Fatal(err)
+	}
+	commitSnapshot(t, v, "add a", map[string]string{"file.txt": "one", "a.txt": "a"})
+	commitSnapshot(t, v, "add b", map[string]string{"file.txt": "one", "a.txt": "a", "b.txt": "b"})
+	if err := os.Remove(filepath.Join(v.Root(), "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	tip := commitSnapshot(t, v, "drop b", map[string]string{"file.txt": "one", "a.txt": "a"})
+
+	writeFile(t, v, "b.txt", "mine")
+
+	err := v.Rebase(upstream, "", false)
+	var conflict *ConflictError
+	if err == nil || errors.As(err, &conflict) {
+		t.Fatalf("Rebase() = %v, want a failure without conflicts", err)
+	}
+
+	state, err := readSequencer(v.root)
+	if err != nil {
+		t.Fatalf("no rebase state after the failed pick: %v", err)
+	}
+	if state.Operation != "rebase" || state.OrigHead != tip || state.Onto != upstream {
+		t.Fatalf("state = %+v, want a rebase of %s onto %s", state, ShortHash(tip), ShortHash(upstream))
+	}
+	if head := readHEAD(v.root); head == tip {
+		t.Fatal("HEAD did not move, the pick should have failed mid-rebase")
+	}
+	return tip
+}
+
+func TestRebaseStoppedAbort(t *testing.T) {
+	v := newTestRepo(t)
+	tip := stoppedRebase(t, v)
+
+	if err := v.RebaseAbort(); err != nil {
+		t.Fatal(err)
+	}
+
+	if head := readHEAD(v.root); head != tip {
+		t.Errorf("HEAD = %s after abort, want %s", ShortHash(head), ShortHash(tip))
+	}
+	checkFile(t, v, "file.txt", "one")
+	checkFile(t, v, "a.txt", "a")
+	checkFile(t, v, "b.txt", "mine")
+	checkNoSequence(t, v)
+}
+
+func TestRebaseStoppedContinue(t *testing.T) {
+	v := newTestRepo(t)
+	stoppedRebase(t, v)
+
+	if err := os.Remove(filepath.Join(v.Root(), "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.RebaseContinue(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"file.txt": "two", "a.txt": "a"}
+	if got := headFiles(t, v); !reflect.DeepEqual(got, want) {
+		t.Errorf("HEAD files = %v, want %v", got, want)
+	}
+	checkFile(t, v, "b.txt", "")
+	checkNoSequence(t, v)
+}
@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"MultiRepoVC/src/internal/utils/time"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ======================================================================
+// REFLOG
+//
+// Every ref update appends a ReflogEntry to .mrvc/logs/<ref>, so earlier
+// positions of HEAD stay recoverable after a bad commit or reset. They
+// are addressable as <ref>@{n}, n = 0 being the current position.
+// ======================================================================
+
+func reflogPath(repoRoot, ref string) string {
+	return filepath.Join(repoRoot, ".mrvc", "logs", filepath.FromSlash(ref))
+}
+
+// appendReflog records one ref movement. The entry is fsynced before the
+// ref itself is renamed into place.
+func appendReflog(repoRoot, ref string, entry model.ReflogEntry) error {
+	path := reflogPath(repoRoot, ref)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeReflog replaces the log of ref with entries (newest first).
+func writeReflog(repoRoot, ref string, entries []model.ReflogEntry) error {
+	var data []byte
+	for i := len(entries) - 1; i >= 0; i-- {
+		line, err := json.Marshal(entries[i])
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return fs.WriteFileAtomic(reflogPath(repoRoot, ref), data, 0644)
+}
+
+// readReflog returns the entries of ref, newest first. A missing log
+// yields no entries. Lines left truncated by a crash are skipped.
+func readReflog(repoRoot, ref string) ([]model.ReflogEntry, error) {
+	f, err := os.Open(reflogPath(repoRoot, ref))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []model.ReflogEntry
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e model.ReflogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// newest first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+func newReflogEntry(oldHash, newHash, author, reason string) model.ReflogEntry {
+	return model.ReflogEntry{
+		Old:       oldHash,
+		New:       newHash,
+		Author:    author,
+		Timestamp: strconv.FormatInt(time.GetCurrentTimestamp(), 10),
+		Reason:    reason,
+	}
+}
+
+// Reflog returns the recorded movements of ref (e.g. "HEAD" or
+// "stash"), newest first.
+func (v *VersionControlV1) Reflog(ref string) ([]model.ReflogEntry, error) {
+	ref, err := reflogRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return readReflog(v.root, ref)
+}
+
+// reflogRef maps the names users give a ref to the ref whose log is
+// kept: "" and "HEAD" are HEAD, "stash" is refs/stash. Names that would
+// leave .mrvc/logs are rejected.
+func reflogRef(ref string) (string, error) {
+	switch ref {
+	case "", "HEAD":
+		return "HEAD", nil
+	case "stash":
+		return stashRef, nil
+	}
+
+	if strings.Contains(ref, "..") || strings.ContainsAny(ref, "\\:") || path.IsAbs(ref) {
+		return "", errors.New("invalid ref name: " + ref)
+	}
+	return ref, nil
+}
+
+// resolveReflog resolves <ref>@{n} to the hash ref pointed at n moves ago.
+func (v *VersionControlV1) resolveReflog(rev string) (string, error) {
+	at := strings.Index(rev, "@{")
+	ref, nth := rev[:at], strings.TrimSuffix(rev[at+2:], "}")
+
+	n, err := strconv.Atoi(nth)
+	if err != nil || n < 0 || !strings.HasSuffix(rev, "}") {
+		return "", errors.New("invalid reflog revision: " + rev)
+	}
+
+	ref, err = reflogRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := readReflog(v.root, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if n >= len(entries) {
+		return "", errors.New("reflog of " + ref + " has only " + strconv.Itoa(len(entries)) + " entries")
+	}
+
+	if entries[n].New == "" {
+		return "", errors.New(rev + " does not point at a commit")
+	}
+	return entries[n].New, nil
+}
+
+// defaultAuthor names whoever moves a ref outside of a commit (e.g. on
+// checkout), see refMover.
+func (v *VersionControlV1) defaultAuthor() string {
+	return v.refMover().String()
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveReflogRevision(t *testing.T) {
+	v := newTestRepo(t)
+	c1 := commitContent(t, v, "one")
+	c2 := commitContent(t, v, "two")
+	c3 := commitContent(t, v, "three")
+
+	checkRevisions(t, v, []revisionTest{
+		{rev: "HEAD@{0}", want: c3},
+		{rev: "HEAD@{1}", want: c2},
+		{rev: "HEAD@{2}", want: c1},
+		{rev: "@{1}", want: c2},
+
+		{rev: "HEAD@{3}", wantErr: "reflog of HEAD has only 3 entries"},
+		{rev: "HEAD@{-1}", wantErr: "invalid reflog revision"},
+		{rev: "HEAD@{x}", wantErr: "invalid reflog revision"},
+		{rev: "HEAD@{1", wantErr: "invalid reflog revision"},
+		{rev: "stash@{0}", wantErr: "reflog of refs/stash has only 0 entries"},
+		{rev: "../../x@{0}", wantErr: "invalid ref name"},
+	})
+}
+
+func TestReflogRefNames(t *testing.T) {
+	v := newTestRepo(t)
+	head := commitContent(t, v, "one")
+
+	entry := newReflogEntry("", head, "tester", "stash: test")
+	if err := appendReflog(v.root, stashRef, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref     string
+		reason  string
+		wantErr bool
+	}{
+		{ref: "", reason: "commit: one"},
+		{ref: "HEAD", reason: "commit: one"},
+		{ref: "stash", reason: "stash: test"},
+		{ref: "refs/stash", reason: "stash: test"},
+		{ref: "refs/heads/none"},
+		{ref: "../../etc/passwd", wantErr: true},
+		{ref: "refs/../../x", wantErr: true},
+		{ref: "/etc/passwd", wantErr: true},
+		{ref: `C:\x`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		entries, err := v.Reflog(tt.ref)
+		switch {
+		case tt.wantErr:
+			if err == nil || !strings.Contains(err.Error(), "invalid ref name") {
+				t.Errorf("Reflog(%q) = %v, %v, want an invalid ref name error", tt.ref, entries, err)
+			}
+		case err != nil:
+			t.Errorf("Reflog(%q) failed: %v", tt.ref, err)
+		case tt.reason == "" && len(entries) != 0:
+			t.Errorf("Reflog(%q) = %v, want no entries", tt.ref, entries)
+		case tt.reason != "" && (len(entries) != 1 || entries[0].Reason != tt.reason):
+			t.Errorf("Reflog(%q) = %v, want one entry %q", tt.ref, entries, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ======================================================================
+// REFS
+//
+// A ref is a file below .mrvc holding a commit hash (HEAD, refs/stash).
+// Updates follow Git's lock protocol:
+//   1. create <ref>.lock exclusively – a second writer fails here
+//   2. compare the ref's current value with the value the caller based
+//      its work on – a concurrent move fails here
+//   3. append the move to the ref's reflog (see reflog.go)
+//   4. write the new value into the lock file and fsync it
+//   5. rename the lock file over the ref – atomic on POSIX filesystems
+//
+// An interrupted update therefore leaves either the old ref (plus a
+// stale lock file) or the new one, never a truncated hash.
+// ======================================================================
+
+// ErrRefMoved is returned when a ref no longer holds the value an update
+// was based on.
+var ErrRefMoved = errors.New("ref was moved by another process")
+
+func refPath(repoRoot, ref string) string {
+	return filepath.Join(repoRoot, ".mrvc", filepath.FromSlash(ref))
+}
+
+// readRef returns the hash stored in ref, or empty if it does not exist.
+func readRef(repoRoot, ref string) string {
+	data, err := os.ReadFile(refPath(repoRoot, ref))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// updateRef moves ref from oldHash to newHash (compare-and-swap) and
+// records the move in the ref's reflog. An empty newHash deletes the ref.
+func updateRef(repoRoot, ref, oldHash, newHash, author, reason string) error {
+	entry := newReflogEntry(strings.TrimSpace(oldHash), strings.TrimSpace(newHash), author, reason)
+	return moveRef(repoRoot, ref, oldHash, newHash, &entry)
+}
+
+// moveRef is updateRef with a prepared reflog entry; a nil entry leaves
+// the reflog untouched (used when the reflog itself was rewritten).
+func moveRef(repoRoot, ref, oldHash, newHash string, entry *model.ReflogEntry) error {
+	path := refPath(repoRoot, ref)
+	lockPath := path + ".lock"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	lock, err := os.OpenFile(lockPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return errors.New(ref + " is locked by another mrvc process (remove " +
+				lockPath + " if no other mrvc process is running)")
+		}
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			lock.Close()
+			os.Remove(lockPath)
+		}
+	}()
+
+	if current := readRef(repoRoot, ref); current != strings.TrimSpace(oldHash) {
+		return fmt.Errorf("%w: %s", ErrRefMoved, ref)
+	}
+
+	if entry != nil {
+		if err := appendReflog(repoRoot, ref, *entry); err != nil {
+			return err
+		}
+	}
+
+	if newHash == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if _, err := lock.WriteString(strings.TrimSpace(newHash)); err != nil {
+		return err
+	}
+	if err := lock.Sync(); err != nil {
+		return err
+	}
+	if err := lock.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(lockPath, path); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,310 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/utils/diff"
+	"bytes"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// ======================================================================
+// RENAME AND COPY DETECTION
+//
+// Snapshots only know paths, so a moved file first shows up as one path
+// gone and one path new. detectRenames pairs them up:
+//   1. identical blob hashes are renames with 100% similarity
+//   2. remaining pairs are scored by the share of lines they have in
+//      common, compared as multisets of line hashes so the cost stays
+//      linear in the file sizes; the best pairs at or above
+//      RenameThreshold win
+//   3. new paths left over are copies when their content equals any
+//      existing file, or is similar to a file modified in the same change
+// ======================================================================
+
+// RenameThreshold is the minimum similarity, in percent, for two files
+// to count as a rename or copy.
+const RenameThreshold = 50
+
+// Rename pairs a new path with the path its content came from.
+type Rename struct {
+	From       string
+	To         string
+	Similarity int  // percent of common content, 100 when identical
+	Copy       bool // From still exists
+}
+
+func (r Rename) String() string {
+	kind := "renamed"
+	if r.Copy {
+		kind = "copied"
+	}
+	return kind + ": " + r.From + " → " + r.To + " (" + strconv.Itoa(r.Similarity) + "%)"
+}
+
+// renameSide is one snapshot taking part in detection: path → blob hash
+// and a way to load a path's content.
+type renameSide struct {
+	files map[string]string
+	load  func(path string) ([]byte, error)
+}
+
+// commitSide reads content from the object store.
+func (v *VersionControlV1) commitSide(files map[string]string) renameSide {
+	return renameSide{
+		files: files,
+		load: func(path string) ([]byte, error) {
+			return readObject(v.root, files[path])
+		},
+	}
+}
+
+// workingSide reads content from the working directory.
+func (v *VersionControlV1) workingSide(files map[string]string) renameSide {
+	return renameSide{
+		files: files,
+		load: func(path string) ([]byte, error) {
+			return os.ReadFile(filepath.Join(v.root, filepath.FromSlash(path)))
+		},
+	}
+}
+
+// detectRenames pairs paths removed from `from` with paths added in
+// `to`, then looks for copy sources of the added paths left over.
+// modified lists paths changed in place, the candidates for similar
+// copies.
+func detectRenames(from, to renameSide, removed, added, modified []string) ([]Rename, error) {
+	removed = sortedCopy(removed)
+	added = sortedCopy(added)
+
+	var renames []Rename
+	usedFrom := make(map[string]bool)
+	usedTo := make(map[string]bool)
+
+	// 1. Exact renames
+	byHash := make(map[string][]string)
+	for _, p := range removed {
+		byHash[from.files[p]] = append(byHash[from.files[p]], p)
+	}
+	for _, p := range added {
+		candidates := byHash[to.files[p]]
+		if len(candidates) == 0 {
+			continue
+		}
+		renames = append(renames, Rename{From: candidates[0], To: p, Similarity: 100})
+		usedFrom[candidates[0]], usedTo[p] = true, true
+		byHash[to.files[p]] = candidates[1:]
+	}
+
+	// 2. Similar renames, best scores first
+	type scored struct {
+		from, to string
+		score    int
+	}
+	var pairs []scored
+
+	cache := newContentCache()
+	for _, f := range removed {
+		if usedFrom[f] {
+			continue
+		}
+		for _, t := range added {
+			if usedTo[t] {
+				continue
+			}
+			score, err := cache.similarity(from, f, to, t)
+			if err != nil {
+				return nil, err
+			}
+			if score >= RenameThreshold {
+				pairs = append(pairs, scored{f, t, score})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].score > pairs[j].score })
+	for _, p := range pairs {
+		if usedFrom[p.from] || usedTo[p.to] {
+			continue
+		}
+		renames = append(renames, Rename{From: p.from, To: p.to, Similarity: p.score})
+		usedFrom[p.from], usedTo[p.to] = true, true
+	}
+
+	// 3. Copies of files that still exist
+	existing := make(map[string]string)
+	for p, h := range from.files {
+		if _, kept := to.files[p]; kept {
+			if _, seen := existing[h]; !seen || p < existing[h] {
+				existing[h] = p
+			}
+		}
+	}
+
+	for _, t := range added {
+		if usedTo[t] {
+			continue
+		}
+
+		if src, ok := existing[to.files[t]]; ok {
+			renames = append(renames, Rename{From: src, To: t, Similarity: 100, Copy: true})
+			continue
+		}
+
+		best := Rename{}
+		for _, m := range sortedCopy(modified) {
+			score, err := cache.similarity(from, m, to, t)
+			if err != nil {
+				return nil, err
+			}
+			if score >= RenameThreshold && score > best.Similarity {
+				best = Rename{From: m, To: t, Similarity: score, Copy: true}
+			}
+		}
+		if best.From != "" {
+			renames = append(renames, best)
+		}
+	}
+
+	sort.Slice(renames, func(i, j int) bool { return renames[i].To < renames[j].To })
+	return renames, nil
+}
+
+// withoutRenamed drops the sources and targets of renames from removed
+// and added. Copies stay: their source still exists and their target is
+// still a new, untracked file.
+func withoutRenamed(removed, added []string, renames []Rename) ([]string, []string) {
+	from := make(map[string]bool)
+	to := make(map[string]bool)
+	for _, r := range renames {
+		if !r.Copy {
+			from[r.From], to[r.To] = true, true
+		}
+	}
+
+	keep := func(paths []string, drop map[string]bool) []string {
+		var out []string
+		for _, p := range paths {
+			if !drop[p] {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	return keep(removed, from), keep(added, to)
+}
+
+// renamedPaths returns the paths renamed away (copy sources stay) and
+// the paths renamed or copied to.
+func renamedPaths(renames []Rename) (from, to map[string]bool) {
+	from = make(map[string]bool)
+	to = make(map[string]bool)
+	for _, r := range renames {
+		if !r.Copy {
+			from[r.From] = true
+		}
+		to[r.To] = true
+	}
+	return from, to
+}
+
+// lineCounts is the content of a text file as a multiset: hash of a
+// line → number of times it occurs.
+type lineCounts struct {
+	counts map[uint64]int
+	lines  int
+	text   bool // false for binary content
+}
+
+// contentCache keeps the line counts of every file across the pairwise
+// comparisons of detectRenames.
+type contentCache struct {
+	files map[string]lineCounts
+}
+
+func newContentCache() *contentCache {
+	return &contentCache{files: make(map[string]lineCounts)}
+}
+
+func (c *contentCache) get(side renameSide, prefix, path string) (lineCounts, error) {
+	key := prefix + path
+	if lc, ok := c.files[key]; ok {
+		return lc, nil
+	}
+
+	content, err := side.load(path)
+	if err != nil {
+		return lineCounts{}, err
+	}
+
+	lc := lineCounts{text: bytes.IndexByte(content, 0) < 0}
+	if lc.text {
+		lc = countLines(content)
+	}
+	c.files[key] = lc
+	return lc, nil
+}
+
+// countLines builds the line multiset of text content.
+func countLines(content []byte) lineCounts {
+	lc := lineCounts{counts: make(map[uint64]int), text: true}
+	for _, line := range diff.SplitLines(string(content)) {
+		h := fnv.New64a()
+		h.Write([]byte(line))
+		lc.counts[h.Sum64()]++
+		lc.lines++
+	}
+	return lc
+}
+
+// similarity scores two files in percent: the lines they share relative
+// to the longer file. Binary files are only ever identical.
+func (c *contentCache) similarity(from renameSide, f string, to renameSide, t string) (int, error) {
+	if from.files[f] == to.files[t] {
+		return 100, nil
+	}
+
+	a, err := c.get(from, "from:", f)
+	if err != nil {
+		return 0, err
+	}
+	b, err := c.get(to, "to:", t)
+	if err != nil {
+		return 0, err
+	}
+	return lineSimilarity(a, b), nil
+}
+
+// lineSimilarity counts the lines a and b have in common regardless of
+// their order, in percent of the longer file. It is at most 99, as 100 is
+// reserved for identical content.
+func lineSimilarity(a, b lineCounts) int {
+	longest := max(a.lines, b.lines)
+	if !a.text || !b.text || longest == 0 {
+		return 0
+	}
+
+	// Not enough lines on the shorter side to ever reach the threshold
+	if min(a.lines, b.lines)*100/longest < RenameThreshold {
+		return 0
+	}
+
+	small, large := a.counts, b.counts
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+
+	common := 0
+	for h, n := range small {
+		common += min(n, large[h])
+	}
+	return min(common*100/longest, 99)
+}
+
+func sortedCopy(s []string) []string {
+	out := append([]string(nil), s...)
+	sort.Strings(out)
+	return out
+}
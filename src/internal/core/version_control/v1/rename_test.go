@@ -0,0 +1,197 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// lines returns n lines "<prefix>0\n" … "<prefix>n-1\n".
+func lines(prefix string, n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&sb, "%s%d\n", prefix, i)
+	}
+	return sb.String()
+}
+
+// memorySide is a snapshot of path → content held in memory.
+func memorySide(contents map[string]string) renameSide {
+	files := make(map[string]string)
+	for p, c := range contents {
+		files[p] = HashContent([]byte(c))
+	}
+	return renameSide{
+		files: files,
+		load: func(path string) ([]byte, error) {
+			c, ok := contents[path]
+			if !ok {
+				return nil, errors.New("no such file: " + path)
+			}
+			return []byte(c), nil
+		},
+	}
+}
+
+func TestLineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"empty", "", "", 0},
+		{"identical content caps at 99", lines("x", 10), lines("x", 10), 99},
+		{"half shared", lines("x", 10), lines("x", 5) + lines("y", 5), 50},
+		{"measured against the longer file", lines("x", 10), lines("x", 8), 80},
+		{"order does not matter", "a\nb\nc\nd\n", "d\nc\nb\na\n", 99},
+		{"duplicates count once per occurrence", "a\na\na\nb\n", "a\nb\nb\nb\n", 50},
+		{"sizes too different", lines("x", 10), lines("x", 4), 0},
+		{"binary", "a\x00b\n", "a\x00b\n", 0},
+		{"disjoint", lines("x", 10), lines("y", 10), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := lineCounts{text: !strings.Contains(tt.a, "\x00")}
+			if a.text {
+				a = countLines([]byte(tt.a))
+			}
+			b := lineCounts{text: !strings.Contains(tt.b, "\x00")}
+			if b.text {
+				b = countLines([]byte(tt.b))
+			}
+
+			if got := lineSimilarity(a, b); got != tt.want {
+				t.Errorf("lineSimilarity = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectRenames(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to map[string]string
+		modified []string
+		want     []Rename
+	}{
+		{
+			name: "exact match",
+			from: map[string]string{"a.txt": "hello\n"},
+			to:   map[string]string{"b.txt": "hello\n"},
+			want: []Rename{{From: "a.txt", To: "b.txt", Similarity: 100}},
+		},
+		{
+			name: "at the threshold",
+			from: map[string]string{"a.txt": lines("x", 100)},
+			to:   map[string]string{"b.txt": lines("x", 50) + lines("y", 50)},
+			want: []Rename{{From: "a.txt", To: "b.txt", Similarity: 50}},
+		},
+		{
+			name: "below the threshold",
+			from: map[string]string{"a.txt": lines("x", 100)},
+			to:   map[string]string{"b.txt": lines("x", 49) + lines("y", 51)},
+			want: nil,
+		},
+		{
+			name: "best score wins",
+			from: map[string]string{"a.txt": lines("x", 10), "b.txt": lines("x", 9) + "other\n"},
+			to:   map[string]string{"c.txt": lines("x", 10) + "new\n"},
+			want: []Rename{{From: "a.txt", To: "c.txt", Similarity: 90}},
+		},
+		{
+			name: "tie goes to the first source path",
+			from: map[string]string{"b.txt": lines("x", 10), "a.txt": lines("x", 10)},
+			to:   map[string]string{"c.txt": lines("x", 9) + "new\n"},
+			want: []Rename{{From: "a.txt", To: "c.txt", Similarity: 90}},
+		},
+		{
+			name: "identical sources pair with targets in path order",
+			from: map[string]string{"b.txt": "same\n", "a.txt": "same\n"},
+			to:   map[string]string{"d.txt": "same\n", "c.txt": "same\n"},
+			want: []Rename{
+				{From: "a.txt", To: "c.txt", Similarity: 100},
+				{From: "b.txt", To: "d.txt", Similarity: 100},
+			},
+		},
+		{
+			name: "exact copy of a kept file",
+			from: map[string]string{"a.txt": "hello\n"},
+			to:   map[string]string{"a.txt": "hello\n", "b.txt": "hello\n"},
+			want: []Rename{{From: "a.txt", To: "b.txt", Similarity: 100, Copy: true}},
+		},
+		{
+			name:     "similar copy of a modified file",
+			from:     map[string]string{"a.txt": lines("x", 10)},
+			to:       map[string]string{"a.txt": lines("x", 10) + "more\n", "b.txt": lines("x", 8) + "b\nb\n"},
+			modified: []string{"a.txt"},
+			want:     []Rename{{From: "a.txt", To: "b.txt", Similarity: 80, Copy: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := memorySide(tt.from), memorySide(tt.to)
+
+			var removed, added []string
+			for p := range tt.from {
+				if _, ok := tt.to[p]; !ok {
+					removed = append(removed, p)
+				}
+			}
+			for p := range tt.to {
+				if _, ok := tt.from[p]; !ok {
+					added = append(added, p)
+				}
+			}
+
+			got, err := detectRenames(from, to, removed, added, tt.modified)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDetectRenamesExactSkipsContent checks the fast path: identical
+// hashes are paired without reading either file.
+func TestDetectRenamesExactSkipsContent(t *testing.T) {
+	failing := func(files map[string]string) renameSide {
+		return renameSide{
+			files: files,
+			load: func(path string) ([]byte, error) {
+				return nil, errors.New("content read for " + path)
+			},
+		}
+	}
+
+	from := failing(map[string]string{"a.txt": "1111"})
+	to := failing(map[string]string{"b.txt": "1111"})
+
+	got, err := detectRenames(from, to, []string{"a.txt"}, []string{"b.txt"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != (Rename{From: "a.txt", To: "b.txt", Similarity: 100}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestWithoutRenamedKeepsCopies(t *testing.T) {
+	renames := []Rename{
+		{From: "a.txt", To: "b.txt", Similarity: 100},
+		{From: "c.txt", To: "d.txt", Similarity: 100, Copy: true},
+	}
+
+	removed, added := withoutRenamed([]string{"a.txt"}, []string{"b.txt", "d.txt"}, renames)
+	if len(removed) != 0 {
+		t.Errorf("removed = %v, want none", removed)
+	}
+	if fmt.Sprint(added) != "[d.txt]" {
+		t.Errorf("added = %v, want the copy target", added)
+	}
+}
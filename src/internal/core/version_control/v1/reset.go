@@ -0,0 +1,57 @@
+package v1
+
+import "errors"
+
+// ResetMode selects how much of the repository state reset rewrites.
+type ResetMode int
+
+const (
+	// ResetSoft moves HEAD only.
+	ResetSoft ResetMode = iota
+	// ResetMixed also resets the index. MRVC has no staging area (see
+	// docs/v1/DesignDoc.md), so it currently behaves like ResetSoft.
+	ResetMixed
+	// ResetHard also rewrites tracked files of the working directory to
+	// the target snapshot. Untracked files and nested repos are kept.
+	ResetHard
+)
+
+func (m ResetMode) String() string {
+	switch m {
+	case ResetSoft:
+		return "soft"
+	case ResetHard:
+		return "hard"
+	default:
+		return "mixed"
+	}
+}
+
+// ======================================================================
+// RESET
+// ======================================================================
+
+// Reset moves HEAD to commit target. rev is the revision as the user
+// typed it and only appears in the reflog entry.
+func (v *VersionControlV1) Reset(target string, rev string, mode ResetMode) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !v.isCommit(target) {
+		return errors.New("not a commit: " + target)
+	}
+
+	head := readHEAD(v.root)
+
+	if mode == ResetHard {
+		// Forced: discarding local changes is the point of --hard
+		if err := v.checkoutTree(head, target, true); err != nil {
+			return err
+		}
+	}
+
+	return updateHEAD(v.root, head, target, v.defaultAuthor(), "reset: moving to "+rev)
+}
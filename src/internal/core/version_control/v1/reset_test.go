@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveAncestry(t *testing.T) {
+	v := newTestRepo(t)
+	c1 := commitContent(t, v, "one")
+	c2 := commitContent(t, v, "two")
+	c3 := commitContent(t, v, "three")
+
+	checkRevisions(t, v, []revisionTest{
+		{rev: "HEAD~", want: c2},
+		{rev: "HEAD~0", want: c3},
+		{rev: "HEAD~1", want: c2},
+		{rev: "HEAD~2", want: c1},
+		{rev: "HEAD^", want: c2},
+		{rev: "HEAD^^", want: c1},
+		{rev: "HEAD^0", want: c3},
+		{rev: "HEAD^1", want: c2},
+		{rev: "HEAD~1^", want: c1},
+		{rev: c3[:8] + "~2", want: c1},
+		{rev: "HEAD@{1}~1", want: c1},
+		{rev: "HEAD@{0}^^", want: c1},
+
+		{rev: "HEAD~3", wantErr: "revision goes past the first commit"},
+		{rev: "HEAD^2", wantErr: "commit has no parent 2"},
+		{rev: "~1", wantErr: "unknown revision"},
+		{rev: "main~1", wantErr: "unknown revision"},
+	})
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		mode     ResetMode
+		wantFile string
+	}{
+		{mode: ResetSoft, wantFile: "three"},
+		{mode: ResetMixed, wantFile: "three"},
+		{mode: ResetHard, wantFile: "one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			v := newTestRepo(t)
+			c1 := commitContent(t, v, "one")
+			commitContent(t, v, "two")
+			c3 := commitContent(t, v, "three")
+
+			untracked := filepath.Join(v.Root(), "notes.txt")
+			if err := os.WriteFile(untracked, []byte("keep"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := v.Reset(c1, "HEAD~2", tt.mode); err != nil {
+				t.Fatal(err)
+			}
+
+			// The old HEAD stays reachable through the reflog
+			checkRevisions(t, v, []revisionTest{
+				{rev: "HEAD", want: c1},
+				{rev: "HEAD@{1}", want: c3},
+			})
+
+			data, err := os.ReadFile(filepath.Join(v.Root(), "file.txt"))
+			if err != nil || string(data) != tt.wantFile {
+				t.Errorf("file.txt = %q, %v, want %q", data, err, tt.wantFile)
+			}
+			if data, err := os.ReadFile(untracked); err != nil || string(data) != "keep" {
+				t.Errorf("untracked notes.txt = %q, %v, want it kept", data, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+)
+
+// ======================================================================
+// REVERT
+//
+// Revert records a new commit undoing the change a commit made against
+// its parent, without rewriting history: the inverse change (commit →
+// parent) is three-way merged onto HEAD.
+// ======================================================================
+
+// Revert creates a commit undoing commitHash, authored by author (zero:
+// the current user). Conflicts stop with a *ConflictError; resolve them
+// and call RevertContinue, or RevertAbort.
+func (v *VersionControlV1) Revert(commitHash string, author model.Identity) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := ensureNoSequence(v.root); err != nil {
+		return err
+	}
+
+	head := readHEAD(v.root)
+	if head == "" {
+		return errors.New("nothing to revert: no commits yet")
+	}
+
+	state := newSequence("revert", head, []string{commitHash})
+	return v.runSequence(state, v.revertPick(author))
+}
+
+// RevertContinue commits the resolved revert.
+func (v *VersionControlV1) RevertContinue(author model.Identity) error {
+	return v.continueOperation("revert", v.revertPick(author))
+}
+
+// RevertAbort drops the revert and restores HEAD and the working files.
+func (v *VersionControlV1) RevertAbort() error {
+	return v.abortOperation("revert")
+}
+
+// revertPick builds the inverse change of a commit, authored by author.
+// A zero author means the current committer.
+func (v *VersionControlV1) revertPick(author model.Identity) pickFunc {
+	return func(_ model.SequencerState, commitHash string) (pick, error) {
+		commit, err := readCommit(v.root, commitHash)
+		if err != nil {
+			return pick{}, err
+		}
+
+		return pick{
+			Commit:  commitHash,
+			Base:    commitHash,
+			Theirs:  commit.Parent,
+			Label:   "parent of " + ShortHash(commitHash) + " (" + subject(commit.Message) + ")",
+			Message: "Revert \"" + subject(commit.Message) + "\"\n\nThis reverts commit " + commitHash + ".",
+			Author:  author,
+		}, nil
+	}
+}
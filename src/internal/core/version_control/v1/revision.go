@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// minPrefix is the shortest abbreviated hash accepted as a revision.
+const minPrefix = 4
+
+// ResolveRevision turns a user-supplied revision into a full commit hash.
+//
+// Supported forms:
+//
+//	HEAD          the current commit
+//	<hash>        a full commit hash
+//	<prefix>      a unique abbreviated commit hash (at least 4 chars)
+//	<ref>@{n}     where ref pointed n moves ago, per its reflog (@{n} = HEAD@{n})
+//	<rev>~n       the n-th first-parent ancestor of rev (<rev>~ = <rev>~1)
+//	<rev>^        the parent of rev; may be repeated (<rev>^^ = <rev>~2)
+func (v *VersionControlV1) ResolveRevision(rev string) (string, error) {
+	rev = strings.TrimSpace(rev)
+
+	// Split "<base><suffixes>", e.g. HEAD@{2}~3^ → HEAD@{2} + ~3^
+	searchFrom := strings.LastIndex(rev, "}") + 1
+	if i := strings.IndexAny(rev[searchFrom:], "~^"); i >= 0 {
+		hash, err := v.ResolveRevision(rev[:searchFrom+i])
+		if err != nil {
+			return "", err
+		}
+		return v.walkAncestors(hash, rev[searchFrom+i:], rev)
+	}
+
+	if strings.Contains(rev, "@{") {
+		return v.resolveReflog(rev)
+	}
+
+	if rev == "HEAD" {
+		head := readHEAD(v.root)
+		if head == "" {
+			return "", errors.New("HEAD does not point at a commit yet")
+		}
+		return head, nil
+	}
+
+	if len(rev) < minPrefix || strings.Trim(strings.ToLower(rev), "0123456789abcdef") != "" {
+		return "", errors.New("unknown revision: " + rev)
+	}
+	rev = strings.ToLower(rev)
+
+	entries, err := os.ReadDir(filepath.Join(v.root, ".mrvc", "objects", rev[:2]))
+	if err != nil {
+		return "", errors.New("unknown revision: " + rev)
+	}
+
+	var matches []string
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), rev[2:]) {
+			continue
+		}
+
+		hash := rev[:2] + e.Name()
+		if v.isCommit(hash) {
+			matches = append(matches, hash)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", errors.New("unknown revision: " + rev)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", errors.New("ambiguous revision: " + rev)
+	}
+}
+
+// isCommit reports whether hash names a stored commit object.
+func (v *VersionControlV1) isCommit(hash string) bool {
+	commit, err := readCommit(v.root, hash)
+	return err == nil && commit.Timestamp != ""
+}
+
+// walkAncestors applies ~n / ^ suffixes to hash, following first parents.
+func (v *VersionControlV1) walkAncestors(hash, suffix, rev string) (string, error) {
+	for len(suffix) > 0 {
+		op := suffix[0]
+		suffix = suffix[1:]
+
+		// Optional count after the operator
+		digits := 0
+		for digits < len(suffix) && suffix[digits] >= '0' && suffix[digits] <= '9' {
+			digits++
+		}
+
+		steps := 1
+		if digits > 0 {
+			n, err := strconv.Atoi(suffix[:digits])
+			if err != nil {
+				return "", errors.New("invalid revision: " + rev)
+			}
+			steps = n
+			suffix = suffix[digits:]
+		}
+
+		// "^n" selects the n-th parent; commits have one parent so far
+		if op == '^' && digits > 0 {
+			if steps == 0 {
+				continue
+			}
+			if steps > 1 {
+				return "", errors.New("commit has no parent " + strconv.Itoa(steps) + ": " + rev)
+			}
+		}
+
+		for ; steps > 0; steps-- {
+			commit, err := readCommit(v.root, hash)
+			if err != nil {
+				return "", err
+			}
+			if commit.Parent == "" {
+				return "", errors.New("revision goes past the first commit: " + rev)
+			}
+			hash = commit.Parent
+		}
+	}
+	return hash, nil
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestRepo initializes a repository in a temporary directory with
+// the user and system config kept out of the way.
+func newTestRepo(t *testing.T) *VersionControlV1 {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("MRVC_CONFIG_SYSTEM", filepath.Join(dir, "system-config"))
+	t.Setenv("MRVC_CONFIG_GLOBAL", filepath.Join(dir, "global-config"))
+	t.Setenv(envAuthorName, "tester")
+	t.Setenv(envAuthorEmail, "tester@example.com")
+
+	root := filepath.Join(dir, "repo")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewAt(root)
+	if err := v.Init("repo", "tester"); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// commitContent commits file.txt with content and returns the new HEAD.
+func commitContent(t *testing.T, v *VersionControlV1, content string) string {
+	t.Helper()
+
+	path := filepath.Join(v.Root(), "file.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Commit(content, NewIdentity("tester", "tester@example.com"), []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	return readHEAD(v.root)
+}
+
+// commitSnapshot writes files (name → content) and commits exactly them,
+// so tracked files missing from files are deleted in the new commit.
+// It returns the new HEAD.
+func commitSnapshot(t *testing.T, v *VersionControlV1, message string, files map[string]string) string {
+	t.Helper()
+
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(v.Root(), name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	if err := v.Commit(message, NewIdentity("tester", "tester@example.com"), paths); err != nil {
+		t.Fatal(err)
+	}
+	return readHEAD(v.root)
+}
+
+// headFiles returns the path → content snapshot of HEAD.
+func headFiles(t *testing.T, v *VersionControlV1) map[string]string {
+	t.Helper()
+
+	files, err := commitFiles(v.root, readHEAD(v.root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := make(map[string]string, len(files))
+	for path, hash := range files {
+		data, err := readObject(v.root, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents[path] = string(data)
+	}
+	return contents
+}
+
+// revisionTest is one case of a ResolveRevision table.
+type revisionTest struct {
+	rev     string
+	want    string
+	wantErr string
+}
+
+func checkRevisions(t *testing.T, v *VersionControlV1, tests []revisionTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		got, err := v.ResolveRevision(tt.rev)
+		switch {
+		case tt.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ResolveRevision(%q) = %q, %v, want error %q", tt.rev, got, err, tt.wantErr)
+			}
+		case err != nil:
+			t.Errorf("ResolveRevision(%q) failed: %v", tt.rev, err)
+		case got != tt.want:
+			t.Errorf("ResolveRevision(%q) = %s, want %s", tt.rev, ShortHash(got), ShortHash(tt.want))
+		}
+	}
+}
+
+func TestResolveRevision(t *testing.T) {
+	v := newTestRepo(t)
+	c1 := commitContent(t, v, "one")
+	c2 := commitContent(t, v, "two")
+
+	checkRevisions(t, v, []revisionTest{
+		{rev: "HEAD", want: c2},
+		{rev: " HEAD ", want: c2},
+		{rev: c1, want: c1},
+		{rev: strings.ToUpper(c1[:8]), want: c1},
+		{rev: c2[:minPrefix], want: c2},
+
+		{rev: "", wantErr: "unknown revision"},
+		{rev: "main", wantErr: "unknown revision"},
+		{rev: c1[:minPrefix-1], wantErr: "unknown revision"},
+		{rev: "zzzz", wantErr: "unknown revision"},
+		{rev: strings.Repeat("0", 64), wantErr: "unknown revision"},
+	})
+}
+
+func TestResolveRevisionEmptyRepo(t *testing.T) {
+	v := newTestRepo(t)
+
+	if got, err := v.ResolveRevision("HEAD"); err == nil {
+		t.Errorf("ResolveRevision(HEAD) = %q before the first commit, want an error", got)
+	}
+}
+
+func TestResolveRevisionAmbiguousPrefix(t *testing.T) {
+	v := newTestRepo(t)
+	head := commitContent(t, v, "one")
+	tree, err := readCommit(v.root, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Store commits until two share a prefix of minPrefix characters
+	var a, b string
+	seen := make(map[string]string)
+	for i := 0; a == ""; i++ {
+		hash, data, err := HashCommit(model.CommitObject{
+			Tree:      tree.Tree,
+			Message:   "commit " + strconv.Itoa(i),
+			Timestamp: "0",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveObject(v.root, hash, data); err != nil {
+			t.Fatal(err)
+		}
+
+		prefix := hash[:minPrefix]
+		if other, ok := seen[prefix]; ok {
+			a, b = other, hash
+		}
+		seen[prefix] = hash
+	}
+
+	// The shortest prefixes that tell a and b apart
+	n := minPrefix
+	for a[n] == b[n] {
+		n++
+	}
+
+	checkRevisions(t, v, []revisionTest{
+		{rev: a[:minPrefix], wantErr: "ambiguous revision"},
+		{rev: a[:n], wantErr: "ambiguous revision"},
+		{rev: a[:n+1], want: a},
+		{rev: b[:n+1], want: b},
+		{rev: a, want: a},
+		{rev: b, want: b},
+	})
+
+	// Blobs and trees are stored alongside commits but are no revisions
+	for _, hash := range []string{HashContent([]byte("one")), tree.Tree} {
+		if got, err := v.ResolveRevision(hash); err == nil {
+			t.Errorf("ResolveRevision(%s) = %q, want an error for a non-commit object", ShortHash(hash), got)
+		}
+	}
+}
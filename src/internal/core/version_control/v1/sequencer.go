@@ -0,0 +1,437 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/diff"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ======================================================================
+// SEQUENCER
+//
+// Revert, cherry-pick and rebase all replay the change a commit made
+// (parent → commit, or the inverse) onto HEAD with a three-way merge, one
+// commit at a time. The remaining work is persisted before every pick
+// until --continue, --skip or --abort: in .mrvc/rebase-merge for a
+// rebase, in .mrvc/sequencer.json otherwise. When a merge conflicts, the
+// merged files and conflict markers are left in the working directory.
+// A pick failing for any other reason stops the operation the same way,
+// unless HEAD has not moved yet and there is nothing to undo.
+// ======================================================================
+
+// ConflictError stops an operation that needs manual resolution.
+type ConflictError struct {
+	Operation string
+	Paths     []string
+}
+
+func (e *ConflictError) Error() string {
+	return "conflicts in:\n  " + strings.Join(e.Paths, "\n  ") +
+		"\nresolve them, then run 'mrvc " + e.Operation + " --continue' (or --abort)"
+}
+
+// ErrNoSequence is returned by --continue / --abort with nothing to do.
+var ErrNoSequence = errors.New("no revert, cherry-pick or rebase in progress")
+
+// rebaseDir holds the state of a stopped rebase.
+func rebaseDir(repoRoot string) string {
+	return filepath.Join(repoRoot, ".mrvc", "rebase-merge")
+}
+
+func sequencerPath(repoRoot, op string) string {
+	if op == "rebase" {
+		return filepath.Join(rebaseDir(repoRoot), "state.json")
+	}
+	return filepath.Join(repoRoot, ".mrvc", "sequencer.json")
+}
+
+func readSequencer(repoRoot string) (model.SequencerState, error) {
+	var state model.SequencerState
+	for _, op := range []string{"rebase", "sequencer"} {
+		path := sequencerPath(repoRoot, op)
+		if fs.FileExists(path) {
+			err := fs.ReadJSON(path, &state)
+			return state, err
+		}
+	}
+	return state, ErrNoSequence
+}
+
+// ensureNoSequence fails while another operation awaits resolution.
+func ensureNoSequence(repoRoot string) error {
+	state, err := readSequencer(repoRoot)
+	if errors.Is(err, ErrNoSequence) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return errors.New("a " + state.Operation + " is in progress, use 'mrvc " +
+		state.Operation + " --continue' or '--abort' first")
+}
+
+// pick is one commit's change to replay onto HEAD.
+type pick struct {
+	Commit  string // commit the change comes from (for the sequencer state)
+	Base    string // commit the change starts from
+	Theirs  string // commit the change leads to
+	Label   string // conflict marker label for the incoming side
+	Message string
+	Author  model.Identity // zero: the current committer
+
+	Amend     bool // replace HEAD instead of committing on top of it
+	DropEmpty bool // silently skip a change already present in HEAD
+}
+
+// pickFunc builds the pick for one commit of a sequence.
+type pickFunc func(state model.SequencerState, commit string) (pick, error)
+
+// applyPick merges p onto HEAD and commits the result. On conflicts,
+// nothing is committed and the merge is returned for the caller to
+// persist.
+func (v *VersionControlV1) applyPick(op string, p pick) (*snapshotMerge, error) {
+	// Refuse before touching any file rather than after the merge
+	if _, err := v.Identity(); err != nil {
+		return nil, err
+	}
+
+	head := readHEAD(v.root)
+
+	ours, err := commitFiles(v.root, head)
+	if err != nil {
+		return nil, err
+	}
+	base, err := commitFiles(v.root, p.Base)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := commitFiles(v.root, p.Theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := v.mergeSnapshots(base, ours, theirs, "HEAD", p.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	target := merged.workingTarget()
+	if err := v.checkOverwrites(ours, target); err != nil {
+		return nil, err
+	}
+	if err := v.writeSnapshot(ours, target, false); err != nil {
+		return nil, err
+	}
+
+	if len(merged.conflicts) > 0 {
+		return &merged, nil
+	}
+
+	if sameSnapshot(ours, merged.files) {
+		if p.DropEmpty {
+			return nil, nil
+		}
+		return nil, errors.New("nothing to " + op + ": " + ShortHash(p.Commit) + " changes nothing on top of HEAD")
+	}
+
+	return nil, v.commitPick(op, p, merged.files)
+}
+
+// commitPick commits the result of p, replacing HEAD for an amend.
+func (v *VersionControlV1) commitPick(op string, p pick, snapshot map[string]string) error {
+	tree, err := v.writeTree(snapshot)
+	if err != nil {
+		return err
+	}
+
+	parent := readHEAD(v.root)
+	reason := op + ": " + subject(p.Message)
+	if p.Amend {
+		head, err := readCommit(v.root, parent)
+		if err != nil {
+			return err
+		}
+		parent = head.Parent
+		reason = op + " (fixup): " + subject(p.Message)
+	}
+
+	committer, err := v.Identity()
+	if err != nil {
+		return err
+	}
+	author := p.Author
+	if author.Name == "" {
+		author = committer
+	}
+
+	_, err = v.writeCommitWithParent(tree, parent, p.Message, author, committer, reason)
+	return err
+}
+
+// runSequence applies state.Current and then every commit of state.Todo,
+// building each pick with makePick. It stops at the first conflict or
+// failed pick, leaving the state saved for --continue.
+func (v *VersionControlV1) runSequence(state model.SequencerState, makePick pickFunc) error {
+	for {
+		state.Snapshot, state.Conflicts = nil, nil
+		if err := fs.WriteJSON(sequencerPath(v.root, state.Operation), state); err != nil {
+			return err
+		}
+
+		p, err := makePick(state, state.Current)
+		if err != nil {
+			return v.stopSequence(state, err)
+		}
+
+		merged, err := v.applyPick(state.Operation, p)
+		if err != nil {
+			return v.stopSequence(state, err)
+		}
+
+		if merged != nil {
+			state.Snapshot = merged.files
+			state.Conflicts = merged.conflictPaths()
+
+			if err := fs.WriteJSON(sequencerPath(v.root, state.Operation), state); err != nil {
+				return err
+			}
+			return &ConflictError{Operation: state.Operation, Paths: state.Conflicts}
+		}
+
+		if len(state.Todo) == 0 {
+			return clearSequencer(v.root, state.Operation)
+		}
+		state.Current, state.Todo = state.Todo[0], state.Todo[1:]
+	}
+}
+
+// stopSequence returns err, the failure of a pick without conflicts.
+// The saved state is kept for --continue, --skip and --abort, unless
+// HEAD is still where the operation started and nothing needs undoing.
+func (v *VersionControlV1) stopSequence(state model.SequencerState, err error) error {
+	if readHEAD(v.root) == state.OrigHead {
+		if cerr := clearSequencer(v.root, state.Operation); cerr != nil {
+			return cerr
+		}
+		return err
+	}
+	return fmt.Errorf("%w\nfix this, then run 'mrvc %s --continue' (or --skip, --abort)", err, state.Operation)
+}
+
+// continueSequence commits the resolved state.Current and resumes the
+// remaining commits. A sequence stopped by a failed pick retries it.
+func (v *VersionControlV1) continueSequence(state model.SequencerState, makePick pickFunc) error {
+	if len(state.Conflicts) == 0 {
+		return v.runSequence(state, makePick)
+	}
+
+	p, err := makePick(state, state.Current)
+	if err != nil {
+		return err
+	}
+
+	snapshot := state.Snapshot
+	if snapshot == nil {
+		snapshot = make(map[string]string)
+	}
+
+	// Conflicted paths are taken as the user resolved them on disk; a
+	// deleted file resolves to a deletion.
+	var unresolved []string
+	for _, rel := range state.Conflicts {
+		abs := filepath.Join(v.root, rel)
+		if !fs.FileExists(abs) {
+			delete(snapshot, rel)
+			continue
+		}
+
+		content, err := os.ReadFile(abs)
+		if err != nil {
+			return err
+		}
+		if diff.HasConflictMarkers(string(content)) {
+			unresolved = append(unresolved, rel)
+			continue
+		}
+
+		hash := HashContent(content)
+		if err := SaveObject(v.root, hash, content); err != nil {
+			return err
+		}
+		snapshot[rel] = hash
+	}
+
+	if len(unresolved) > 0 {
+		return errors.New("conflict markers remain in:\n  " + strings.Join(unresolved, "\n  "))
+	}
+
+	if err := v.commitPick(state.Operation, p, snapshot); err != nil {
+		return err
+	}
+
+	if len(state.Todo) == 0 {
+		return clearSequencer(v.root, state.Operation)
+	}
+
+	state.Current, state.Todo = state.Todo[0], state.Todo[1:]
+	return v.runSequence(state, makePick)
+}
+
+// skipSequence drops the stopped state.Current, restoring the files it
+// touched to HEAD, and resumes the remaining commits.
+func (v *VersionControlV1) skipSequence(state model.SequencerState, makePick pickFunc) error {
+	if err := v.restoreFromConflict(state, readHEAD(v.root)); err != nil {
+		return err
+	}
+
+	if len(state.Todo) == 0 {
+		return clearSequencer(v.root, state.Operation)
+	}
+
+	state.Current, state.Todo = state.Todo[0], state.Todo[1:]
+	state.Snapshot, state.Conflicts = nil, nil
+	return v.runSequence(state, makePick)
+}
+
+// abortSequence restores the working directory and HEAD to how they were
+// before the operation started.
+func (v *VersionControlV1) abortSequence(state model.SequencerState) error {
+	if err := v.restoreFromConflict(state, state.OrigHead); err != nil {
+		return err
+	}
+
+	head := readHEAD(v.root)
+	if head != state.OrigHead {
+		if err := updateHEAD(v.root, head, state.OrigHead, v.defaultAuthor(), state.Operation+": abort"); err != nil {
+			return err
+		}
+	}
+
+	return clearSequencer(v.root, state.Operation)
+}
+
+// restoreFromConflict rewrites the files touched by a stopped merge to
+// the snapshot of commit target. Files only the merge created are removed;
+// local edits of files the merge did not touch are kept. A sequence
+// stopped by a failed pick left HEAD's files, which are switched instead.
+func (v *VersionControlV1) restoreFromConflict(state model.SequencerState, target string) error {
+	targetFiles, err := commitFiles(v.root, target)
+	if err != nil {
+		return err
+	}
+
+	if len(state.Conflicts) == 0 {
+		current, err := commitFiles(v.root, readHEAD(v.root))
+		if err != nil {
+			return err
+		}
+		return v.writeSnapshot(current, targetFiles, false)
+	}
+
+	current := make(map[string]string, len(state.Snapshot)+len(state.Conflicts))
+	for p, h := range state.Snapshot {
+		current[p] = h
+	}
+	for _, p := range state.Conflicts {
+		current[p] = ""
+	}
+
+	return v.writeSnapshot(current, targetFiles, false)
+}
+
+func clearSequencer(repoRoot, op string) error {
+	if op == "rebase" {
+		return os.RemoveAll(rebaseDir(repoRoot))
+	}
+
+	err := os.Remove(sequencerPath(repoRoot, op))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// sameSnapshot reports whether two path → blob snapshots are identical.
+func sameSnapshot(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for p, h := range a {
+		if b[p] != h {
+			return false
+		}
+	}
+	return true
+}
+
+// newSequence starts the state of an operation applying commits in order.
+func newSequence(op, head string, commits []string) model.SequencerState {
+	return model.SequencerState{
+		Operation: op,
+		OrigHead:  head,
+		Current:   commits[0],
+		Todo:      commits[1:],
+	}
+}
+
+// continueOperation resumes a stopped op (see continueSequence).
+func (v *VersionControlV1) continueOperation(op string, makePick pickFunc) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := v.sequenceFor(op)
+	if err != nil {
+		return err
+	}
+	return v.continueSequence(state, makePick)
+}
+
+// skipOperation skips a stopped op's commit (see skipSequence).
+func (v *VersionControlV1) skipOperation(op string, makePick pickFunc) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := v.sequenceFor(op)
+	if err != nil {
+		return err
+	}
+	return v.skipSequence(state, makePick)
+}
+
+// abortOperation cancels a stopped op (see abortSequence).
+func (v *VersionControlV1) abortOperation(op string) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := v.sequenceFor(op)
+	if err != nil {
+		return err
+	}
+	return v.abortSequence(state)
+}
+
+// sequenceFor loads the persisted state, which must belong to op.
+func (v *VersionControlV1) sequenceFor(op string) (model.SequencerState, error) {
+	state, err := readSequencer(v.root)
+	if err != nil {
+		return state, err
+	}
+	if state.Operation != op {
+		return state, errors.New("a " + state.Operation + " is in progress, not a " + op)
+	}
+	return state, nil
+}
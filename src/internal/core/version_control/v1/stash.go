@@ -0,0 +1,259 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ======================================================================
+// STASH
+//
+// A stash entry is a commit whose parent is HEAD at stash time and whose
+// tree is the dirty working state. The newest entry is referenced from
+// .mrvc/refs/stash; older ones live in that ref's reflog, so entries are
+// addressed as stash@{n} and dropping one rewrites the reflog.
+// ======================================================================
+
+const stashRef = "refs/stash"
+
+// StashPush records the local changes of the given paths (all tracked
+// files when empty) as a stash entry and reverts them to HEAD. Untracked
+// files are included only with includeUntracked. It returns the entry's
+// commit hash.
+func (v *VersionControlV1) StashPush(message string, paths []string, includeUntracked bool) (string, error) {
+	unlock, err := v.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	head := readHEAD(v.root)
+	if head == "" {
+		return "", errors.New("cannot stash: no commits yet")
+	}
+
+	headCommit, err := readCommit(v.root, head)
+	if err != nil {
+		return "", err
+	}
+	headFiles, err := commitFiles(v.root, head)
+	if err != nil {
+		return "", err
+	}
+
+	stash, err := v.stashSnapshot(headFiles, v.pathFilter(paths), includeUntracked)
+	if err != nil {
+		return "", err
+	}
+	if sameSnapshot(stash, headFiles) {
+		return "", errors.New("no local changes to save")
+	}
+
+	tree, err := v.writeTree(stash)
+	if err != nil {
+		return "", err
+	}
+
+	if message == "" {
+		message = "WIP on " + ShortHash(head) + ": " + subject(headCommit.Message)
+	} else {
+		message = "On " + ShortHash(head) + ": " + message
+	}
+
+	committer, err := v.Identity()
+	if err != nil {
+		return "", err
+	}
+	hash, err := v.storeCommit(tree, head, message, committer, committer)
+	if err != nil {
+		return "", err
+	}
+
+	if err := updateRef(v.root, stashRef, readRef(v.root, stashRef), hash, committer.String(), message); err != nil {
+		return "", err
+	}
+
+	// Only the stashed files differ between both snapshots
+	return hash, v.writeSnapshot(stash, headFiles, false)
+}
+
+// StashList returns the stash entries, stash@{0} first. Each entry's
+// New field is the stash commit and Reason its message.
+func (v *VersionControlV1) StashList() ([]model.ReflogEntry, error) {
+	return readReflog(v.root, stashRef)
+}
+
+// StashApply merges stash@{n} into the working directory. On conflicts
+// the files keep conflict markers and the entry is kept.
+func (v *VersionControlV1) StashApply(n int) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return v.stashApply(n)
+}
+
+// StashPop applies stash@{n} and drops it unless the apply failed.
+func (v *VersionControlV1) StashPop(n int) error {
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := v.stashApply(n); err != nil {
+		return err
+	}
+	_, err = v.stashDrop(n)
+	return err
+}
+
+// StashDrop removes stash@{n} and returns its commit hash.
+func (v *VersionControlV1) StashDrop(n int) (string, error) {
+	unlock, err := v.lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	return v.stashDrop(n)
+}
+
+func (v *VersionControlV1) stashApply(n int) error {
+	entries, err := v.stashEntries(n)
+	if err != nil {
+		return err
+	}
+
+	stash, err := readCommit(v.root, entries[n].New)
+	if err != nil {
+		return err
+	}
+
+	base, err := commitFiles(v.root, stash.Parent)
+	if err != nil {
+		return err
+	}
+	theirs, err := commitFiles(v.root, entries[n].New)
+	if err != nil {
+		return err
+	}
+	ours, err := commitFiles(v.root, readHEAD(v.root))
+	if err != nil {
+		return err
+	}
+
+	merged, err := v.mergeSnapshots(base, ours, theirs, "Updated upstream", "Stashed changes")
+	if err != nil {
+		return err
+	}
+
+	target := merged.workingTarget()
+	if err := v.checkOverwrites(ours, target); err != nil {
+		return err
+	}
+	if err := v.writeSnapshot(ours, target, false); err != nil {
+		return err
+	}
+
+	if len(merged.conflicts) > 0 {
+		return errors.New("conflicts in:\n  " + strings.Join(merged.conflictPaths(), "\n  ") +
+			"\nthe stash entry is kept")
+	}
+	return nil
+}
+
+func (v *VersionControlV1) stashDrop(n int) (string, error) {
+	entries, err := v.stashEntries(n)
+	if err != nil {
+		return "", err
+	}
+
+	dropped := entries[n].New
+	rest := append(entries[:n:n], entries[n+1:]...)
+
+	if err := writeReflog(v.root, stashRef, rest); err != nil {
+		return "", err
+	}
+
+	if n == 0 {
+		top := ""
+		if len(rest) > 0 {
+			top = rest[0].New
+		}
+		if err := moveRef(v.root, stashRef, dropped, top, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if len(rest) == 0 {
+		if err := os.Remove(reflogPath(v.root, stashRef)); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return dropped, nil
+}
+
+// stashEntries loads the stash reflog and checks that stash@{n} exists.
+func (v *VersionControlV1) stashEntries(n int) ([]model.ReflogEntry, error) {
+	entries, err := readReflog(v.root, stashRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("no stash entries")
+	}
+	if n < 0 || n >= len(entries) {
+		return nil, errors.New("stash@{" + strconv.Itoa(n) + "} does not exist")
+	}
+	return entries, nil
+}
+
+// stashSnapshot is headFiles with the working state of every selected
+// path applied: modified and deleted tracked files and, optionally,
+// untracked files. Changed blobs are saved to the object store.
+func (v *VersionControlV1) stashSnapshot(headFiles map[string]string, selected func(string) bool, includeUntracked bool) (map[string]string, error) {
+	working, err := v.workingFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	stash := make(map[string]string, len(headFiles))
+	for rel, hash := range headFiles {
+		stash[rel] = hash
+	}
+
+	for rel := range headFiles {
+		if _, exists := working[rel]; !exists && selected(rel) {
+			delete(stash, rel)
+		}
+	}
+
+	for rel, abs := range working {
+		_, tracked := headFiles[rel]
+		if !selected(rel) || (!tracked && !includeUntracked) {
+			continue
+		}
+
+		content, err := os.ReadFile(abs)
+		if err != nil {
+			return nil, err
+		}
+
+		hash := HashContent(content)
+		if hash == headFiles[rel] {
+			continue
+		}
+		if err := SaveObject(v.root, hash, content); err != nil {
+			return nil, err
+		}
+		stash[rel] = hash
+	}
+
+	return stash, nil
+}
@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStatusWithoutUntracked(t *testing.T) {
+	v := newTestRepo(t)
+	body := lines("line", 20)
+	commitSnapshot(t, v, "files", map[string]string{"moved.txt": body, "kept.txt": "kept\n", "gone.txt": "gone\n"})
+
+	// moved.txt → renamed.txt, kept.txt copied to copy.txt, gone.txt
+	// deleted and new.txt added
+	if err := os.Rename(filepath.Join(v.Root(), "moved.txt"), filepath.Join(v.Root(), "renamed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(v.Root(), "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, v, "copy.txt", "kept\n")
+	writeFile(t, v, "new.txt", "new\n")
+
+	status, err := v.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Renamed) != 2 {
+		t.Fatalf("Renamed = %v, want the rename and the copy", status.Renamed)
+	}
+
+	hidden := status.WithoutUntracked()
+	if len(hidden.Untracked) != 0 || len(hidden.Renamed) != 0 {
+		t.Errorf("untracked %v and renamed %v left, want both hidden", hidden.Untracked, hidden.Renamed)
+	}
+	if want := []string{"gone.txt", "moved.txt"}; !reflect.DeepEqual(hidden.Deleted, want) {
+		t.Errorf("Deleted = %v, want %v", hidden.Deleted, want)
+	}
+	if !reflect.DeepEqual(status.Deleted, []string{"gone.txt"}) {
+		t.Errorf("WithoutUntracked changed the original: Deleted = %v", status.Deleted)
+	}
+	if hidden.Clean() {
+		t.Error("status is clean with a deleted file")
+	}
+}
@@ -4,20 +4,81 @@ import (
 	"MultiRepoVC/src/internal/core/version_control/v1/model"
 	"MultiRepoVC/src/internal/utils/fs"
 	"MultiRepoVC/src/internal/utils/time"
-	"encoding/json"
+	"MultiRepoVC/src/internal/utils/uuid"
 	"errors"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 )
 
-type VersionControlV1 struct{}
+// VersionControlV1 operates on the repository rooted at root.
+type VersionControlV1 struct {
+	root string
+}
 
+// New returns a VersionControlV1 for the repository in the current
+// working directory.
 func New() *VersionControlV1 {
-	return &VersionControlV1{}
+	return NewAt(fs.GetCurrentDir())
+}
+
+// NewAt returns a VersionControlV1 for the repository rooted at root.
+// Relative file paths passed to its methods are resolved against root.
+func NewAt(root string) *VersionControlV1 {
+	return &VersionControlV1{root: fs.NormalizePath(root)}
+}
+
+// Root returns the absolute, slash-normalized repository root.
+func (v *VersionControlV1) Root() string {
+	return v.root
+}
+
+// resolvePath makes p absolute relative to the repository root and
+// normalizes it.
+func (v *VersionControlV1) resolvePath(p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(v.root, p)
+	}
+	return fs.NormalizePath(p)
+}
+
+// repoRelPath converts a user-supplied path into a slash-separated path
+// relative to the repository root.
+func (v *VersionControlV1) repoRelPath(p string) (string, error) {
+	rel, err := filepath.Rel(v.root, v.resolvePath(p))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.New("path is outside the repository: " + p)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// pathFilter matches repo-relative paths against pathspecs: a file or
+// a directory prefix. No pathspecs select everything.
+func (v *VersionControlV1) pathFilter(paths []string) func(string) bool {
+	var specs []string
+	for _, p := range paths {
+		rel, err := v.repoRelPath(p)
+		if err != nil {
+			continue
+		}
+		specs = append(specs, rel)
+	}
+
+	return func(rel string) bool {
+		if len(paths) == 0 {
+			return true
+		}
+		for _, spec := range specs {
+			if spec == "." || rel == spec || strings.HasPrefix(rel, spec+"/") {
+				return true
+			}
+		}
+		return false
+	}
 }
 
 // ======================================================================
@@ -25,8 +86,7 @@ func New() *VersionControlV1 {
 // ======================================================================
 
 func (v *VersionControlV1) Init(repoName string, author string) error {
-	root := fs.GetCurrentDir()
-	mrvc := filepath.Join(root, ".mrvc")
+	mrvc := filepath.Join(v.root, ".mrvc")
 
 	if fs.IsDirPresent(mrvc) {
 		return errors.New("repository already initialized")
@@ -36,10 +96,16 @@ func (v *VersionControlV1) Init(repoName string, author string) error {
 		return err
 	}
 
+	repoID, err := uuid.New()
+	if err != nil {
+		return err
+	}
+
 	meta := model.Metadata{
 		Name:      repoName,
 		Author:    author,
 		CreatedAt: strconv.FormatInt(time.GetCurrentTimestamp(), 10),
+		RepoID:    repoID,
 	}
 
 	return fs.WriteJSON(filepath.Join(mrvc, "metadata.json"), meta)
@@ -49,12 +115,18 @@ func (v *VersionControlV1) Init(repoName string, author string) error {
 // COMMIT
 // ======================================================================
 
-func (v *VersionControlV1) Commit(message string, author string, files []string) error {
+func (v *VersionControlV1) Commit(message string, author model.Identity, files []string) error {
 	if len(files) == 0 {
 		return errors.New("no files to commit")
 	}
 
-	repoRoot := fs.GetCurrentDir()
+	unlock, err := v.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	repoRoot := v.root
 
 	// -----------------------------
 	// Wildcard "*" → commit all files
@@ -75,8 +147,11 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 		}
 	} else {
 		for i, f := range files {
-			normalized := fs.NormalizePath(f)
+			normalized := v.resolvePath(f)
 			files[i] = normalized
+			if !strings.HasPrefix(normalized, repoRoot+"/") {
+				return errors.New("file is outside the repository: " + normalized)
+			}
 			if !fs.FileExists(normalized) {
 				return errors.New("file does not exist: " + normalized)
 			}
@@ -84,18 +159,11 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 	}
 
 	// -----------------------------
-	// Build directory → TreeObject
+	// Store blobs → snapshot
 	// -----------------------------
-	directoryTrees := make(map[string]model.TreeObject)
-
-	// Parent → children mapping (optimization)
-	children := make(map[string][]string)
+	snapshot := make(map[string]string, len(files))
 
 	for _, filePath := range files {
-
-		// --------------------------------------
-		// 1. Blob
-		// --------------------------------------
 		//TODO stream large files instead of reading all at once
 		content, err := os.ReadFile(filePath)
 		if err != nil {
@@ -104,19 +172,65 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 
 		blobHash := HashContent(content)
 
-		if err := SaveObject(blobHash, content); err != nil {
+		if err := SaveObject(repoRoot, blobHash, content); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(repoRoot, filePath)
+		if err != nil {
 			return err
 		}
+		snapshot[filepath.ToSlash(rel)] = blobHash
+	}
+
+	rootTreeHash, err := v.writeTree(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// Without a configured user, whoever the caller named as the author
+	// (--author or the anonymous author) also records the commit
+	committer, err := v.Identity()
+	if errors.Is(err, ErrNoIdentity) {
+		committer = author
+	} else if err != nil {
+		return err
+	}
+
+	commitHash, err := v.writeCommit(rootTreeHash, message, author, committer, "commit: "+subject(message))
+	if err != nil {
+		return err
+	}
+
+	log.Println("Commit created:", commitHash)
+	return nil
+}
+
+// writeTree stores the tree objects for a path → blobHash snapshot and
+// returns the root tree hash.
+func (v *VersionControlV1) writeTree(snapshot map[string]string) (string, error) {
+	// -----------------------------
+	// Build directory → TreeObject
+	// -----------------------------
+	// Directories are keyed by their slash-separated path relative to the
+	// repo root; the root itself is "".
+	directoryTrees := map[string]model.TreeObject{
+		"": {Entries: []model.TreeEntry{}},
+	}
+
+	// Parent → children mapping (optimization)
+	children := make(map[string][]string)
+
+	for rel, blobHash := range snapshot {
 
 		// --------------------------------------
-		// 2. Determine directory of file
+		// 1. Determine directory of file
 		// --------------------------------------
-		fileDir := filepath.Dir(filePath)
+		fileDir := path.Dir(rel)
 		if fileDir == "." { //if file is in repo root directory, set to repo root
-			fileDir = repoRoot
+			fileDir = ""
 		}
 
-		fileDir = fs.NormalizePath(fileDir)
 		if _, exists := directoryTrees[fileDir]; !exists {
 			directoryTrees[fileDir] = model.TreeObject{Entries: []model.TreeEntry{}}
 		}
@@ -124,22 +238,21 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 		// Add file entry into this directory tree
 		tree := directoryTrees[fileDir]
 		tree = addOrReplaceTreeEntry(tree, model.TreeEntry{
-			Name:      filepath.Base(filePath),
+			Name:      path.Base(rel),
 			EntryType: "blob",
 			Hash:      blobHash,
 		})
 		directoryTrees[fileDir] = tree
 
 		// --------------------------------------
-		// 3. Ensure all parent directories exist
+		// 2. Ensure all parent directories exist
 		// --------------------------------------
 		current := fileDir
-		for current != repoRoot {
-			parent := filepath.Dir(current)
+		for current != "" {
+			parent := path.Dir(current)
 			if parent == "." {
-				parent = repoRoot
+				parent = ""
 			}
-			parent = fs.NormalizePath(parent)
 
 			if _, ok := directoryTrees[parent]; !ok {
 				directoryTrees[parent] = model.TreeObject{Entries: []model.TreeEntry{}}
@@ -170,8 +283,15 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 		dirs = append(dirs, d)
 	}
 
+	depth := func(dir string) int {
+		if dir == "" {
+			return 0
+		}
+		return strings.Count(dir, "/") + 1
+	}
+
 	sort.Slice(dirs, func(i, j int) bool {
-		return strings.Count(dirs[i], "/") > strings.Count(dirs[j], "/")
+		return depth(dirs[i]) > depth(dirs[j])
 	})
 	// ==================================================================
 	// BUILD TREES BOTTOM-UP (single pass)  O(N)
@@ -194,7 +314,7 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 		// Add subtree entries
 		for _, child := range children[dir] {
 			tree = addOrReplaceTreeEntry(tree, model.TreeEntry{
-				Name:      filepath.Base(child),
+				Name:      path.Base(child),
 				EntryType: "tree",
 				Hash:      treeHashes[child],
 			})
@@ -207,186 +327,264 @@ func (v *VersionControlV1) Commit(message string, author string, files []string)
 
 		hash, jsonBytes, err := HashTree(tree)
 		if err != nil {
-			return err
+			return "", err
 		}
 
-		if err := SaveObject(hash, jsonBytes); err != nil {
-			return err
+		if err := SaveObject(v.root, hash, jsonBytes); err != nil {
+			return "", err
 		}
 
 		treeHashes[dir] = hash
 	}
 
-	rootTreeHash := treeHashes[repoRoot]
+	return treeHashes[""], nil
+}
+
+// writeCommit stores a commit of tree on top of HEAD and moves HEAD to
+// it, logging reason in the reflog. It returns the new commit hash.
+func (v *VersionControlV1) writeCommit(tree, message string, author, committer model.Identity, reason string) (string, error) {
+	return v.writeCommitWithParent(tree, readHEAD(v.root), message, author, committer, reason)
+}
+
+// writeCommitWithParent is writeCommit with an explicit parent, e.g.
+// HEAD's parent to replace HEAD (amend).
+func (v *VersionControlV1) writeCommitWithParent(tree, parent, message string, author, committer model.Identity, reason string) (string, error) {
+	head := readHEAD(v.root)
+
+	commitHash, err := v.storeCommit(tree, parent, message, author, committer)
+	if err != nil {
+		return "", err
+	}
+
+	if err := updateHEAD(v.root, head, commitHash, committer.String(), reason); err != nil {
+		return "", err
+	}
+
+	return commitHash, nil
+}
+
+// storeCommit saves a commit object without moving any ref.
+func (v *VersionControlV1) storeCommit(tree, parent, message string, author, committer model.Identity) (string, error) {
+	// ==================================================================
+	// SNAPSHOT NESTED REPOS
+	//
+	// Every commit records which repos are nested in this one and where
+	// (see docs/v1/NestedRepo.md). Only identity, name and path are
+	// stored, so commits inside a nested repo never affect these hashes.
+	// ==================================================================
+	nestedHashes, err := v.snapshotNestedRepos()
+	if err != nil {
+		return "", err
+	}
 
 	// ==================================================================
 	// CREATE COMMIT OBJECT
 	// ==================================================================
 
 	commit := model.CommitObject{
-		Tree:      rootTreeHash,
-		Parent:    readHEAD(),
-		Message:   message,
-		Author:    author,
-		Timestamp: strconv.FormatInt(time.GetCurrentTimestamp(), 10),
+		Tree:        tree,
+		Parent:      parent,
+		Message:     message,
+		Author:      author,
+		Committer:   committer,
+		Timestamp:   committer.Timestamp,
+		NestedRepos: nestedHashes,
 	}
 
 	commitHash, commitBytes, err := HashCommit(commit)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	if err := SaveObject(commitHash, commitBytes); err != nil {
-		return err
+	if err := SaveObject(v.root, commitHash, commitBytes); err != nil {
+		return "", err
 	}
 
-	err = updateHEAD(commitHash)
+	return commitHash, nil
+}
+
+// snapshotNestedRepos saves a NestedRepoObject for every repo directly
+// nested in this one and returns their hashes in path order.
+func (v *VersionControlV1) snapshotNestedRepos() ([]string, error) {
+	nested, _, err := v.workingNestedRepos()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Println("Commit created:", commitHash)
-	return nil
+	hashes := make([]string, 0, len(nested))
+	for _, n := range nested {
+		hash, jsonBytes, err := HashNestedRepo(n)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := SaveObject(v.root, hash, jsonBytes); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
 }
 
 // ======================================================================
 // STATUS
 // ======================================================================
 
-func (v *VersionControlV1) Status() (string, error) {
-	repoRoot := fs.GetCurrentDir()
+// StatusResult describes how the working directory differs from HEAD.
+// All path lists are sorted.
+type StatusResult struct {
+	Head        string // empty when there are no commits yet
+	Modified    []string
+	Deleted     []string
+	Untracked   []string
+	Renamed     []Rename // renames and copies into untracked paths
+	NestedRepos []NestedRepoChange
+	Warnings    []string
+}
 
-	head := readHEAD()
-	if head == "" {
-		return "No commits yet.", nil
+// Clean reports whether nothing differs from HEAD.
+func (s StatusResult) Clean() bool {
+	return len(s.Modified) == 0 && len(s.Deleted) == 0 && len(s.Untracked) == 0 &&
+		len(s.Renamed) == 0 && len(s.NestedRepos) == 0 && len(s.Warnings) == 0
+}
+
+// WithoutUntracked hides untracked files, including the renames and
+// copies into them (status.showUntracked = false). The sources of hidden
+// renames are reported as deleted again.
+func (s StatusResult) WithoutUntracked() StatusResult {
+	deleted := append([]string(nil), s.Deleted...)
+	for _, r := range s.Renamed {
+		if !r.Copy {
+			deleted = append(deleted, r.From)
+		}
 	}
+	sort.Strings(deleted)
 
-	// ------------------------------------------------------
-	// Load HEAD commit
-	// ------------------------------------------------------
-	commitPath := filepath.Join(".mrvc", "objects", head[:2], head[2:])
-	commitBytes, err := os.ReadFile(commitPath)
-	if err != nil {
-		return "", err
+	s.Deleted, s.Untracked, s.Renamed = deleted, nil, nil
+	return s
+}
+
+// String renders the status for humans.
+func (s StatusResult) String() string {
+	if s.Head == "" {
+		return "No commits yet."
+	}
+	if s.Clean() {
+		return "clean"
 	}
 
-	var commit model.CommitObject
-	if err := json.Unmarshal(commitBytes, &commit); err != nil {
-		return "", err
+	var sb strings.Builder
+
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		sb.WriteString(title + ":\n")
+		for _, l := range lines {
+			sb.WriteString("  " + l + "\n")
+		}
+		sb.WriteString("\n")
 	}
 
-	// ------------------------------------------------------
-	// Load HEAD tree
-	// ------------------------------------------------------
-	treeHash := commit.Tree
-	treePath := filepath.Join(".mrvc", "objects", treeHash[:2], treeHash[2:])
-	treeBytes, err := os.ReadFile(treePath)
-	if err != nil {
-		return "", err
+	renamed := make([]string, len(s.Renamed))
+	for i, r := range s.Renamed {
+		renamed[i] = r.String()
+	}
+	nested := make([]string, len(s.NestedRepos))
+	for i, c := range s.NestedRepos {
+		nested[i] = c.String()
 	}
 
-	var headTree model.TreeObject
-	if err := json.Unmarshal(treeBytes, &headTree); err != nil {
-		return "", err
+	section("Modified", s.Modified)
+	section("Deleted", s.Deleted)
+	section("Renamed", renamed)
+	section("Untracked", s.Untracked)
+	section("Nested repos", nested)
+
+	for _, w := range s.Warnings {
+		sb.WriteString("Warning: " + w + "\n")
 	}
 
-	// Convert HEAD tree to map path → hash
-	headFiles := make(map[string]string)
-	err = flattenTree(repoRoot, "", headTree, headFiles)
+	return sb.String()
+}
+
+func (v *VersionControlV1) Status() (StatusResult, error) {
+	repoRoot := v.root
+
+	head := readHEAD(repoRoot)
+	if head == "" {
+		return StatusResult{}, nil
+	}
+
+	// Convert HEAD snapshot to map path → hash
+	headFiles, err := commitFiles(repoRoot, head)
 	if err != nil {
-		return "", err
+		return StatusResult{}, err
 	}
 
 	// ------------------------------------------------------
 	// Scan working directory
 	// ------------------------------------------------------
-	workingFiles, err := fs.ListFiles(repoRoot, fs.WalkOptions{
-		IgnoreMRVC:          true,
-		IgnoreNestedRepos:   true,
-		ApplyIgnorePatterns: true,
-	})
+	workingFiles, err := v.hashWorkingFiles()
 	if err != nil {
-		return "", err
-	}
-
-	// Normalize paths to match headFiles keys
-	normalized := make([]string, 0, len(workingFiles))
-	for _, f := range workingFiles {
-		normalized = append(normalized, fs.NormalizePath(f))
+		return StatusResult{}, err
 	}
 
 	// ------------------------------------------------------
 	// Compare
 	// ------------------------------------------------------
-	var modified []string
-	var deleted []string
-	var untracked []string
-
-	seen := make(map[string]bool)
-
-	for _, w := range normalized {
-		rel, _ := filepath.Rel(repoRoot, w)
-		rel = filepath.ToSlash(rel)
-
-		seen[rel] = true
+	result := StatusResult{Head: head}
 
+	for rel, currentHash := range workingFiles {
 		// In HEAD?
 		headHash, exists := headFiles[rel]
 		if !exists {
-			untracked = append(untracked, rel)
+			result.Untracked = append(result.Untracked, rel)
 			continue
 		}
 
-		// Compare content hash
-		currentHash, err := fs.CalculateFileHash(w)
-		if err != nil {
-			return "", err
-		}
-
 		if currentHash != headHash {
-			modified = append(modified, rel)
+			result.Modified = append(result.Modified, rel)
 		}
 	}
 
 	// Deleted files: in HEAD but not in working dir
 	for rel := range headFiles {
-		if !seen[rel] {
-			deleted = append(deleted, rel)
+		if _, exists := workingFiles[rel]; !exists {
+			result.Deleted = append(result.Deleted, rel)
 		}
 	}
 
 	// ------------------------------------------------------
-	// Build output
+	// Renames and copies: deleted or tracked → untracked
 	// ------------------------------------------------------
-	var sb strings.Builder
-
-	if len(modified) == 0 && len(deleted) == 0 && len(untracked) == 0 {
-		return "clean", nil
+	result.Renamed, err = detectRenames(v.commitSide(headFiles), v.workingSide(workingFiles),
+		result.Deleted, result.Untracked, result.Modified)
+	if err != nil {
+		return StatusResult{}, err
 	}
 
-	if len(modified) > 0 {
-		sb.WriteString("Modified:\n")
-		for _, m := range modified {
-			sb.WriteString("  " + m + "\n")
-		}
-		sb.WriteString("\n")
-	}
+	result.Deleted, result.Untracked = withoutRenamed(result.Deleted, result.Untracked, result.Renamed)
 
-	if len(deleted) > 0 {
-		sb.WriteString("Deleted:\n")
-		for _, d := range deleted {
-			sb.WriteString("  " + d + "\n")
-		}
-		sb.WriteString("\n")
+	sort.Strings(result.Modified)
+	sort.Strings(result.Deleted)
+	sort.Strings(result.Untracked)
+
+	// ------------------------------------------------------
+	// Nested repos: matched by repo_id against HEAD
+	// ------------------------------------------------------
+	headNested, err := commitNestedRepos(repoRoot, head)
+	if err != nil {
+		return StatusResult{}, err
 	}
 
-	if len(untracked) > 0 {
-		sb.WriteString("Untracked:\n")
-		for _, u := range untracked {
-			sb.WriteString("  " + u + "\n")
-		}
-		sb.WriteString("\n")
+	workingNested, warnings, err := v.workingNestedRepos()
+	if err != nil {
+		return StatusResult{}, err
 	}
 
-	return sb.String(), nil
+	result.NestedRepos = diffNestedRepos(headNested, workingNested)
+	result.Warnings = warnings
+
+	return result, nil
 }
@@ -0,0 +1,161 @@
+package v1
+
+import (
+	"MultiRepoVC/src/internal/core/version_control/v1/model"
+	"MultiRepoVC/src/internal/utils/fs"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspaceManifestVersion is the manifest format written by ExportWorkspace.
+const WorkspaceManifestVersion = 1
+
+// ExportWorkspace describes the hierarchy rooted at root: every repo's
+// identity, location, pinned HEAD commit and where to clone it from.
+// Sources are the repos' current absolute paths.
+func ExportWorkspace(root string) (model.WorkspaceManifest, error) {
+	repos, err := DiscoverRepos(root)
+	if err != nil {
+		return model.WorkspaceManifest{}, err
+	}
+
+	manifest := model.WorkspaceManifest{
+		Version: WorkspaceManifestVersion,
+		Repos:   make([]model.WorkspaceRepo, 0, len(repos)),
+	}
+
+	for _, r := range repos {
+		manifest.Repos = append(manifest.Repos, model.WorkspaceRepo{
+			RepoID: r.Metadata.RepoID,
+			Name:   r.Metadata.Name,
+			Path:   r.RelPath,
+			Commit: readHEAD(r.Path),
+			Source: r.Path,
+		})
+	}
+
+	return manifest, nil
+}
+
+// SyncRepo clones entry.Source into dest, or updates the repo already
+// living there, then checks out the pinned commit. It returns what
+// happened: "cloned", "updated" or "up to date".
+//
+// Updating refuses to overwrite local modifications unless force is set,
+// in which case the pinned snapshot is restored even if HEAD matches.
+func SyncRepo(entry model.WorkspaceRepo, dest string, force bool) (string, error) {
+	if !fs.IsDirPresent(filepath.Join(entry.Source, ".mrvc")) {
+		return "", errors.New("source is not an mrvc repository: " + entry.Source)
+	}
+
+	sourceMeta, err := ReadMetadata(entry.Source)
+	if err != nil {
+		return "", err
+	}
+	if !sameRepo(entry.RepoID, sourceMeta.RepoID) {
+		return "", errors.New("source " + entry.Source + " is a different repository than " + entry.Name)
+	}
+
+	action := "cloned"
+	destMRVC := filepath.Join(dest, ".mrvc")
+
+	if fs.IsDirPresent(destMRVC) {
+		destMeta, err := ReadMetadata(dest)
+		if err != nil {
+			return "", err
+		}
+		if !sameRepo(entry.RepoID, destMeta.RepoID) {
+			return "", errors.New(dest + " already holds a different repository than " + entry.Name)
+		}
+		action = "updated"
+	} else {
+		if err := fs.CreateDir(destMRVC); err != nil {
+			return "", err
+		}
+		if err := fs.WriteJSON(filepath.Join(destMRVC, "metadata.json"), sourceMeta); err != nil {
+			return "", err
+		}
+	}
+
+	unlock, err := lockRepo(dest)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if err := copyObjects(entry.Source, dest); err != nil {
+		return "", err
+	}
+
+	if entry.Commit == "" {
+		return action, nil
+	}
+
+	if !fs.FileExists(objectPath(dest, entry.Commit)) {
+		return "", errors.New("pinned commit " + entry.Commit + " not found in " + entry.Source)
+	}
+
+	if action == "updated" && !force && readHEAD(dest) == entry.Commit {
+		return "up to date", nil
+	}
+
+	if err := NewAt(dest).checkoutCommit(entry.Commit, force, "sync: pinned "+entry.Commit); err != nil {
+		return "", err
+	}
+	return action, nil
+}
+
+// sameRepo compares two repo_ids, treating a missing id (repos created
+// before ids existed) as compatible with anything.
+func sameRepo(a, b string) bool {
+	return a == "" || b == "" || a == b
+}
+
+// copyObjects copies every object of source's store that dest lacks.
+// Objects are content-addressed, so existing ones never need rewriting.
+func copyObjects(source, dest string) error {
+	srcObjects := filepath.Join(source, ".mrvc", "objects")
+	dstObjects := filepath.Join(dest, ".mrvc", "objects")
+
+	if !fs.IsDirPresent(srcObjects) {
+		return nil
+	}
+
+	return filepath.Walk(srcObjects, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		// Leftover temp files of interrupted writes are not objects
+		if strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcObjects, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dstObjects, rel)
+		if fs.FileExists(target) {
+			return nil
+		}
+
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.CreateDir(filepath.Dir(dst)); err != nil {
+		return err
+	}
+
+	return fs.WriteFileAtomic(dst, data, 0644)
+}
@@ -2,6 +2,10 @@ package arg
 
 import "strings"
 
+// Rest is the key under which ParseArgs stores every token that follows
+// a bare "--", e.g. the command in `mrvc foreach -- make test`.
+const Rest = "--"
+
 // ParseArgs converts CLI args into a key → []values map.
 //
 // Supports:
@@ -10,36 +14,132 @@ import "strings"
 //	--flag
 //	positional values
 //	--key=value
+//	-k (a single-letter short flag, same as --k)
+//	-kvalue (a declared short value flag with its value attached)
+//	-abc (declared short bool flags grouped, same as -a -b -c)
+//	-- everything after a bare "--" verbatim
 //
 // All non-flag values following a flag are grouped under it
-// until the next --flag is found.
+// until the next --flag is found. Tokens after a bare "--" are not
+// parsed at all and are stored under the Rest key.
 func ParseArgs(args []string) map[string][]string {
+	return ParseArgsWithSpecs(args, nil)
+}
+
+// Spec tells the parser how to treat one declared flag.
+type Spec struct {
+	Name  string // stored under this key
+	Short string // one-letter alias: -s is stored under Name
+	Bool  bool   // never takes values
+	Multi bool   // takes every value up to the next flag
+}
+
+// ParseArgsWithSpecs is ParseArgs for commands that declare their flags.
+// A declared flag that is neither Bool nor Multi takes a single value,
+// so in
+//
+//	--hard HEAD~1          (Bool)
+//	--format oneline HEAD  (single value)
+//
+// "HEAD~1" and "HEAD" stay positional. A declared value flag without a
+// value is stored with no values. Short aliases are stored under their
+// long name. A short value flag may carry its value attached, as in -n5,
+// and short bool flags may be grouped, as in -in for -i -n. Undeclared
+// flags are parsed as by ParseArgs.
+func ParseArgsWithSpecs(args []string, specs []Spec) map[string][]string {
 	result := make(map[string][]string)
 
+	byName := make(map[string]Spec, len(specs))
+	for _, s := range specs {
+		byName[s.Name] = s
+		if s.Short != "" {
+			byName[s.Short] = s
+		}
+	}
+
 	currentKey := "positional"
 
+	// isValue reports whether token can be the value of a flag
+	isValue := func(token string) bool {
+		_, _, attached := attachedValue(byName, token)
+		_, grouped := boolGroup(byName, token)
+		return !isFlag(token) && !attached && !grouped && token != Rest
+	}
+
+	// next returns where the values following a flag go
+	next := func(key string) string {
+		if s, ok := byName[key]; ok && !s.Multi {
+			return "positional"
+		}
+		return key
+	}
+
 	for i := 0; i < len(args); i++ {
 		token := args[i]
 
+		// Case: "--" → stop parsing, keep the remainder as-is
+		if token == Rest {
+			result[Rest] = append(result[Rest], args[i+1:]...)
+			break
+		}
+
 		// Case: --key=value
 		if strings.HasPrefix(token, "--") && strings.Contains(token, "=") {
 			parts := strings.SplitN(token[2:], "=", 2)
-			key := parts[0]
-			value := parts[1]
+			key := canonical(byName, parts[0])
 
-			result[key] = append(result[key], value)
-			currentKey = key
+			result[key] = append(result[key], parts[1])
+			currentKey = next(key)
+			continue
+		}
+
+		// Case: -kvalue
+		if spec, value, ok := attachedValue(byName, token); ok {
+			result[spec.Name] = append(result[spec.Name], value)
+			currentKey = "positional"
+			continue
+		}
+
+		// Case: -abc
+		if names, ok := boolGroup(byName, token); ok {
+			for _, name := range names {
+				result[name] = append(result[name], "true")
+			}
+			currentKey = "positional"
 			continue
 		}
 
-		// Case: --flag or --key
-		if strings.HasPrefix(token, "--") {
-			key := token[2:]
+		// Case: --flag or --key, or a short -k
+		if key, ok := flagKey(token); ok {
+			key = canonical(byName, key)
+			spec, declared := byName[key]
+
+			// Declared boolean → following values are positional again
+			if declared && spec.Bool {
+				result[key] = append(result[key], "true")
+				currentKey = "positional"
+				continue
+			}
 
 			// Next item is a value unless it is another flag
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
-				// Assign upcoming values to this key
-				currentKey = key
+			if i+1 < len(args) && isValue(args[i+1]) {
+				if !declared || spec.Multi {
+					// Assign upcoming values to this key
+					currentKey = key
+					continue
+				}
+				result[key] = append(result[key], args[i+1])
+				i++
+				currentKey = "positional"
+				continue
+			}
+
+			if declared {
+				// A value flag missing its value
+				if _, ok := result[key]; !ok {
+					result[key] = []string{}
+				}
+				currentKey = "positional"
 				continue
 			}
 
@@ -55,3 +155,100 @@ func ParseArgs(args []string) map[string][]string {
 
 	return result
 }
+
+// canonical maps a short alias to its flag's name.
+func canonical(byName map[string]Spec, key string) string {
+	if s, ok := byName[key]; ok {
+		return s.Name
+	}
+	return key
+}
+
+// flagKey returns the key of a --key or -k token.
+func flagKey(token string) (string, bool) {
+	if strings.HasPrefix(token, "--") {
+		return token[2:], true
+	}
+	if len(token) == 2 && token[0] == '-' && isLetter(token[1]) {
+		return token[1:], true
+	}
+	return "", false
+}
+
+// attachedValue splits a -kvalue token whose k is the short alias of a
+// declared flag that takes values.
+func attachedValue(byName map[string]Spec, token string) (Spec, string, bool) {
+	if len(token) < 3 || token[0] != '-' || !isLetter(token[1]) {
+		return Spec{}, "", false
+	}
+	spec, ok := byName[token[1:2]]
+	if !ok || spec.Short != token[1:2] || spec.Bool {
+		return Spec{}, "", false
+	}
+	return spec, token[2:], true
+}
+
+// boolGroup returns the names of the flags in a -abc token whose every
+// letter is the short alias of a declared bool flag.
+func boolGroup(byName map[string]Spec, token string) ([]string, bool) {
+	if len(token) < 3 || token[0] != '-' {
+		return nil, false
+	}
+
+	var names []string
+	for i := 1; i < len(token); i++ {
+		short := token[i : i+1]
+		spec, ok := byName[short]
+		if !isLetter(token[i]) || !ok || spec.Short != short || !spec.Bool {
+			return nil, false
+		}
+		names = append(names, spec.Name)
+	}
+	return names, true
+}
+
+func isFlag(token string) bool {
+	_, ok := flagKey(token)
+	return ok
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// ParseGroups splits args into repeated groups, each starting at an
+// occurrence of --<groupKey>, and parses every group with
+// ParseArgsWithSpecs.
+//
+//	--author kuku --repo a --message "m1" --files x --repo b --message "m2" --files *
+//
+// yields shared = {author: [kuku]} and two groups, each holding its own
+// "repo", "message" and "files" values. Tokens before the first group
+// start belong to shared. groups is empty when --<groupKey> is absent.
+func ParseGroups(args []string, groupKey string, specs []Spec) (shared map[string][]string, groups []map[string][]string) {
+	flag := "--" + groupKey
+
+	start := -1
+	for i, token := range args {
+		if token == Rest {
+			break
+		}
+		if token != flag && !strings.HasPrefix(token, flag+"=") {
+			continue
+		}
+
+		if start == -1 {
+			shared = ParseArgsWithSpecs(args[:i], specs)
+		} else {
+			groups = append(groups, ParseArgsWithSpecs(args[start:i], specs))
+		}
+		start = i
+	}
+
+	if start == -1 {
+		return ParseArgsWithSpecs(args, specs), nil
+	}
+
+	groups = append(groups, ParseArgsWithSpecs(args[start:], specs))
+	return shared, groups
+}
@@ -0,0 +1,168 @@
+package arg
+
+import (
+	"reflect"
+	"testing"
+)
+
+var logSpecs = []Spec{
+	{Name: "max-count", Short: "n"},
+	{Name: "format"},
+	{Name: "ignore-case", Short: "i", Bool: true},
+	{Name: "files-with-matches", Short: "l", Bool: true},
+	{Name: "repo", Multi: true},
+}
+
+func TestParseArgsWithSpecs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want map[string][]string
+	}{
+		{
+			name: "long value flag keeps later values positional",
+			args: []string{"--format", "oneline", "HEAD"},
+			want: map[string][]string{"format": {"oneline"}, "positional": {"HEAD"}},
+		},
+		{
+			name: "key=value",
+			args: []string{"--format=json", "HEAD~1"},
+			want: map[string][]string{"format": {"json"}, "positional": {"HEAD~1"}},
+		},
+		{
+			name: "short flag with separate value",
+			args: []string{"-n", "5", "HEAD"},
+			want: map[string][]string{"max-count": {"5"}, "positional": {"HEAD"}},
+		},
+		{
+			name: "short flag with attached value",
+			args: []string{"-n5", "HEAD"},
+			want: map[string][]string{"max-count": {"5"}, "positional": {"HEAD"}},
+		},
+		{
+			name: "attached value is not taken as the value of the flag before it",
+			args: []string{"--format", "-n5"},
+			want: map[string][]string{"format": {}, "max-count": {"5"}},
+		},
+		{
+			name: "bool short flag takes no value",
+			args: []string{"-i", "foo"},
+			want: map[string][]string{"ignore-case": {"true"}, "positional": {"foo"}},
+		},
+		{
+			name: "bool short flag never takes an attached value",
+			args: []string{"-ix"},
+			want: map[string][]string{"positional": {"-ix"}},
+		},
+		{
+			name: "grouped bool short flags",
+			args: []string{"-il", "foo"},
+			want: map[string][]string{"ignore-case": {"true"}, "files-with-matches": {"true"}, "positional": {"foo"}},
+		},
+		{
+			name: "a value flag cannot be grouped",
+			args: []string{"-in"},
+			want: map[string][]string{"positional": {"-in"}},
+		},
+		{
+			name: "grouped bool flags are not taken as the value of the flag before them",
+			args: []string{"--format", "-li"},
+			want: map[string][]string{"format": {}, "ignore-case": {"true"}, "files-with-matches": {"true"}},
+		},
+		{
+			name: "undeclared short letter with more characters stays positional",
+			args: []string{"-x5"},
+			want: map[string][]string{"positional": {"-x5"}},
+		},
+		{
+			name: "value flag without a value",
+			args: []string{"--format"},
+			want: map[string][]string{"format": {}},
+		},
+		{
+			name: "multi flag takes values up to the next flag",
+			args: []string{"--repo", "a", "!b", "-n", "1"},
+			want: map[string][]string{"repo": {"a", "!b"}, "max-count": {"1"}},
+		},
+		{
+			name: "rest is kept verbatim",
+			args: []string{"-n2", "--", "-n", "--format"},
+			want: map[string][]string{"max-count": {"2"}, Rest: {"-n", "--format"}},
+		},
+		{
+			name: "undeclared flag collects values",
+			args: []string{"--files", "a", "b"},
+			want: map[string][]string{"files": {"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseArgsWithSpecs(tt.args, logSpecs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseArgsWithSpecs(%q) = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroups(t *testing.T) {
+	specs := []Spec{
+		{Name: "message", Short: "m"},
+		{Name: "author"},
+		{Name: "repo"},
+		{Name: "files", Multi: true},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantShared map[string][]string
+		wantGroups []map[string][]string
+	}{
+		{
+			name:       "no groups",
+			args:       []string{"-m", "msg", "--files", "a", "b"},
+			wantShared: map[string][]string{"message": {"msg"}, "files": {"a", "b"}},
+		},
+		{
+			name: "shared flags and two groups",
+			args: []string{"--author", "kuku", "--repo", "a", "--message", "m1", "--files", "x",
+				"--repo", "b", "-m", "m2", "--files", "*"},
+			wantShared: map[string][]string{"author": {"kuku"}},
+			wantGroups: []map[string][]string{
+				{"repo": {"a"}, "message": {"m1"}, "files": {"x"}},
+				{"repo": {"b"}, "message": {"m2"}, "files": {"*"}},
+			},
+		},
+		{
+			name:       "group key with =",
+			args:       []string{"--repo=a", "-mone", "--repo=b", "--files", "y", "z"},
+			wantShared: map[string][]string{},
+			wantGroups: []map[string][]string{
+				{"repo": {"a"}, "message": {"one"}},
+				{"repo": {"b"}, "files": {"y", "z"}},
+			},
+		},
+		{
+			name:       "group key after -- does not start a group",
+			args:       []string{"--repo", "a", "--", "--repo", "b"},
+			wantShared: map[string][]string{},
+			wantGroups: []map[string][]string{
+				{"repo": {"a"}, Rest: {"--repo", "b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shared, groups := ParseGroups(tt.args, "repo", specs)
+			if !reflect.DeepEqual(shared, tt.wantShared) {
+				t.Errorf("shared = %v, want %v", shared, tt.wantShared)
+			}
+			if !reflect.DeepEqual(groups, tt.wantGroups) {
+				t.Errorf("groups = %v, want %v", groups, tt.wantGroups)
+			}
+		})
+	}
+}
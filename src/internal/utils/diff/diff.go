@@ -0,0 +1,137 @@
+package diff
+
+import "strings"
+
+// SplitLines splits text into lines, keeping each line's "\n" so that
+// joining the result reproduces text exactly (including a missing final
+// newline).
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Match pairs line A of the first sequence with line B of the second.
+type Match struct {
+	A, B int
+}
+
+// Matches returns a longest common subsequence of a and b as index pairs
+// in ascending order, using the linear-space variant of Myers' O(ND)
+// algorithm. Lines not covered by a match were deleted from a or inserted
+// into b.
+func Matches(a, b []string) []Match {
+	var matches []Match
+	lcs(a, b, 0, 0, &matches)
+	return matches
+}
+
+// lcs appends the matches between a and b, whose first lines are at
+// aOff and bOff of the original sequences, to out. It splits the problem
+// at the middle snake of the shortest edit script and recurses on both
+// halves, so memory stays linear in len(a)+len(b).
+func lcs(a, b []string, aOff, bOff int, out *[]Match) {
+	// Common prefix and suffix never need the expensive search
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		*out = append(*out, Match{A: aOff + prefix, B: bOff + prefix})
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	midAOff, midBOff := aOff+prefix, bOff+prefix
+
+	if len(midA) > 0 && len(midB) > 0 {
+		x, y, u, v := middleSnake(midA, midB)
+
+		lcs(midA[:x], midB[:y], midAOff, midBOff, out)
+		for i := 0; i < u-x; i++ {
+			*out = append(*out, Match{A: midAOff + x + i, B: midBOff + y + i})
+		}
+		lcs(midA[u:], midB[v:], midAOff+u, midBOff+v, out)
+	}
+
+	for i := suffix; i > 0; i-- {
+		*out = append(*out, Match{A: aOff + len(a) - i, B: bOff + len(b) - i})
+	}
+}
+
+// middleSnake runs the Myers search from both ends of a and b at once
+// and returns the diagonal (x, y) → (u, v) where the two searches meet.
+// It lies on a shortest edit script and splits it into two halves of
+// about the same cost. a and b must not be empty and must differ in
+// their first and last lines.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+
+	max := (n + m + 1) / 2
+	offset := max + 1
+
+	// forward[offset+k]: furthest x on diagonal k = x - y from (0, 0);
+	// backward[offset+k]: the same for the reversed sequences, i.e.
+	// counted back from (n, m)
+	forward := make([]int, 2*max+3)
+	backward := make([]int, 2*max+3)
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1] // step down: insertion
+			} else {
+				x = forward[offset+k-1] + 1 // step right: deletion
+			}
+			y := x - k
+
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// the backward search has done d-1 steps on its diagonal delta-k
+			if rk := delta - k; odd && rk >= -(d-1) && rk <= d-1 && x+backward[offset+rk] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if fk := delta - k; !odd && fk >= -d && fk <= d && x+forward[offset+fk] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+
+	// unreachable: the searches meet after at most max steps each
+	return 0, 0, 0, 0
+}
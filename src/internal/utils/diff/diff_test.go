@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// lcsLength is the textbook O(NM) dynamic program, used as the oracle.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				cur[j] = prev[j-1] + 1
+			case prev[j] >= cur[j-1]:
+				cur[j] = prev[j]
+			default:
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// checkMatches fails unless matches is a common subsequence of a and b
+// as long as the longest one.
+func checkMatches(t *testing.T, a, b []string, matches []Match) {
+	t.Helper()
+
+	for i, m := range matches {
+		if m.A < 0 || m.A >= len(a) || m.B < 0 || m.B >= len(b) {
+			t.Fatalf("match %d out of range: %+v", i, m)
+		}
+		if a[m.A] != b[m.B] {
+			t.Fatalf("match %d pairs different lines: %q vs %q", i, a[m.A], b[m.B])
+		}
+		if i > 0 && (m.A <= matches[i-1].A || m.B <= matches[i-1].B) {
+			t.Fatalf("matches not ascending at %d: %+v after %+v", i, m, matches[i-1])
+		}
+	}
+
+	if want := lcsLength(a, b); len(matches) != want {
+		t.Fatalf("got %d matches, longest common subsequence has %d", len(matches), want)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"both empty", "", "", 0},
+		{"a empty", "", "xyz", 0},
+		{"b empty", "xyz", "", 0},
+		{"equal", "abcdef", "abcdef", 6},
+		{"disjoint", "abc", "xyz", 0},
+		{"insertion", "abcd", "abXcd", 4},
+		{"deletion", "abXcd", "abcd", 4},
+		{"replace middle", "abcde", "abXYe", 3},
+		{"paper example", "abcabba", "cbabac", 4},
+		{"reordered", "abcd", "dcba", 1},
+		{"odd delta", "abcdefg", "xbdfz", 3},
+		{"even delta", "abcdef", "bxdyfz", 3},
+		{"repeated lines", "aaaa", "aa", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := strings.Split(tt.a, ""), strings.Split(tt.b, "")
+			if tt.a == "" {
+				a = nil
+			}
+			if tt.b == "" {
+				b = nil
+			}
+
+			matches := Matches(a, b)
+			if len(matches) != tt.want {
+				t.Fatalf("got %d matches, want %d: %+v", len(matches), tt.want, matches)
+			}
+			checkMatches(t, a, b, matches)
+		})
+	}
+}
+
+func TestMatchesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+
+	random := func() []string {
+		s := make([]string, rng.Intn(40))
+		for i := range s {
+			s[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return s
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		checkMatches(t, a, b, Matches(a, b))
+	}
+}
+
+// TestMatchesLarge would need gigabytes with a full Myers trace.
+func TestMatchesLarge(t *testing.T) {
+	a := make([]string, 10000)
+	b := make([]string, 10000)
+	for i := range a {
+		a[i] = "old " + strconv.Itoa(i)
+		b[i] = "new " + strconv.Itoa(i)
+	}
+	b[5000] = a[5000]
+
+	matches := Matches(a, b)
+	if len(matches) != 1 || matches[0] != (Match{A: 5000, B: 5000}) {
+		t.Fatalf("got %+v, want the single shared line", matches)
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a\n"}},
+		{"a\nb", []string{"a\n", "b"}},
+		{"a\n\nb\n", []string{"a\n", "\n", "b\n"}},
+	}
+
+	for _, tt := range tests {
+		got := SplitLines(tt.text)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
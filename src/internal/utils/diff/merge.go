@@ -0,0 +1,111 @@
+package diff
+
+import "strings"
+
+// Merge3 merges the changes base → ours and base → theirs (diff3).
+//
+// The files are cut into alternating stable chunks (lines unchanged on
+// both sides) and unstable chunks. An unstable chunk changed on one side
+// only takes that side; changed identically on both takes either; changed
+// differently on both becomes a conflict written as
+//
+//	<<<<<<< oursLabel
+//	...ours...
+//	=======
+//	...theirs...
+//	>>>>>>> theirsLabel
+//
+// It returns the merged lines and the number of conflicts.
+func Merge3(base, ours, theirs []string, oursLabel, theirsLabel string) ([]string, int) {
+	// base index → ours / theirs index, -1 when the line is gone
+	toOurs := matchIndex(base, ours)
+	toTheirs := matchIndex(base, theirs)
+
+	var out []string
+	conflicts := 0
+
+	i, a, b := 0, 0, 0
+	for i < len(base) || a < len(ours) || b < len(theirs) {
+		// Stable line: unchanged on both sides
+		if i < len(base) && toOurs[i] == a && toTheirs[i] == b {
+			out = append(out, base[i])
+			i, a, b = i+1, a+1, b+1
+			continue
+		}
+
+		// Unstable chunk runs up to the next line both sides kept
+		k := i
+		for k < len(base) && (toOurs[k] < 0 || toTheirs[k] < 0) {
+			k++
+		}
+
+		ea, eb := len(ours), len(theirs)
+		if k < len(base) {
+			ea, eb = toOurs[k], toTheirs[k]
+		}
+
+		baseChunk, oursChunk, theirsChunk := base[i:k], ours[a:ea], theirs[b:eb]
+
+		switch {
+		case equal(oursChunk, baseChunk):
+			out = append(out, theirsChunk...)
+		case equal(theirsChunk, baseChunk), equal(oursChunk, theirsChunk):
+			out = append(out, oursChunk...)
+		default:
+			conflicts++
+			out = append(out, "<<<<<<< "+oursLabel+"\n")
+			out = append(out, terminated(oursChunk)...)
+			out = append(out, "=======\n")
+			out = append(out, terminated(theirsChunk)...)
+			out = append(out, ">>>>>>> "+theirsLabel+"\n")
+		}
+
+		i, a, b = k, ea, eb
+	}
+
+	return out, conflicts
+}
+
+// HasConflictMarkers reports whether text still contains a conflict
+// block written by Merge3.
+func HasConflictMarkers(text string) bool {
+	return strings.Contains(text, "<<<<<<< ") &&
+		strings.Contains(text, "\n=======\n") &&
+		strings.Contains(text, ">>>>>>> ")
+}
+
+// matchIndex maps every line of base to its matched line in other.
+func matchIndex(base, other []string) []int {
+	idx := make([]int, len(base))
+	for i := range idx {
+		idx[i] = -1
+	}
+	for _, m := range Matches(base, other) {
+		idx[m.A] = m.B
+	}
+	return idx
+}
+
+func equal(x, y []string) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// terminated makes sure a chunk ends in a newline so the following
+// conflict marker starts on its own line.
+func terminated(chunk []string) []string {
+	if len(chunk) == 0 || strings.HasSuffix(chunk[len(chunk)-1], "\n") {
+		return chunk
+	}
+
+	out := append([]string(nil), chunk...)
+	out[len(out)-1] += "\n"
+	return out
+}
@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		want               string
+		conflicts          int
+	}{
+		{
+			name: "no changes",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "ours only",
+			base: "a\nb\nc\n", ours: "a\nB\nc\n", theirs: "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "theirs only",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nb\nC\n",
+			want: "a\nb\nC\n",
+		},
+		{
+			name: "both sides, different lines",
+			base: "a\nb\nc\nd\n", ours: "A\nb\nc\nd\n", theirs: "a\nb\nc\nD\n",
+			want: "A\nb\nc\nD\n",
+		},
+		{
+			name: "identical change",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nX\nc\n",
+			want: "a\nX\nc\n",
+		},
+		{
+			name: "conflict",
+			base: "a\nb\nc\n", ours: "a\nours\nc\n", theirs: "a\ntheirs\nc\n",
+			want:      "a\n<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> other\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "conflict against deletion",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nB\nc\n",
+			want:      "a\n<<<<<<< HEAD\n=======\nB\n>>>>>>> other\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "two conflicts",
+			base: "1\n2\n3\n4\n5\n", ours: "1\nx\n3\ny\n5\n", theirs: "1\nX\n3\nY\n5\n",
+			want: "1\n<<<<<<< HEAD\nx\n=======\nX\n>>>>>>> other\n3\n" +
+				"<<<<<<< HEAD\ny\n=======\nY\n>>>>>>> other\n5\n",
+			conflicts: 2,
+		},
+		{
+			name: "conflict without final newline",
+			base: "a\nb", ours: "a\nc", theirs: "a\nd",
+			want:      "a\n<<<<<<< HEAD\nc\n=======\nd\n>>>>>>> other\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, conflicts := Merge3(SplitLines(tt.base), SplitLines(tt.ours), SplitLines(tt.theirs), "HEAD", "other")
+
+			if got := strings.Join(out, ""); got != tt.want {
+				t.Errorf("merged:\n%s\nwant:\n%s", got, tt.want)
+			}
+			if conflicts != tt.conflicts {
+				t.Errorf("got %d conflicts, want %d", conflicts, tt.conflicts)
+			}
+			if got := HasConflictMarkers(strings.Join(out, "")); got != (tt.conflicts > 0) {
+				t.Errorf("HasConflictMarkers = %v", got)
+			}
+		})
+	}
+}
@@ -51,7 +51,62 @@ func WriteJSON(path string, data any) error {
 		return err
 	}
 
-	return os.WriteFile(path, bytes, 0644)
+	return WriteFileAtomic(path, bytes, 0644)
+}
+
+// WriteFileAtomic writes data to path so that readers, or the file left
+// behind after a crash, only ever see the old or the new content.
+//
+// The data goes to a temp file in the same directory, is fsynced and then
+// renamed over path; the directory is fsynced so the rename is durable.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	// Any failure below leaves path untouched; drop the temp file
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	committed = true
+
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes directory metadata (e.g. a rename) to disk. Not every
+// platform can open directories, so failures are ignored.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
 }
 
 // FileExists checks whether a file exists.
@@ -174,9 +229,9 @@ func ListFiles(rootDir string, opts WalkOptions) ([]string, error) {
 		}
 
 		// ------------------------------------------------
-		// 2. Skip nested repos -> any folder with its own .mrvc
+		// 2. Skip nested repos -> any folder below rootDir with its own .mrvc
 		// ------------------------------------------------
-		if opts.IgnoreNestedRepos && info.IsDir() {
+		if opts.IgnoreNestedRepos && info.IsDir() && path != rootDir {
 			if IsDirPresent(filepath.Join(path, ".mrvc")) {
 				return filepath.SkipDir
 			}
@@ -11,3 +11,38 @@ func GetCurrentTimestamp() int64 {
 func FormatISO(ms int64) string {
 	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
 }
+
+// FormatISOZone formats millis in the zone offsetMinutes east of UTC:
+// 2025-11-21T20:22:11+02:00 (or ...Z for UTC).
+func FormatISOZone(ms int64, offsetMinutes int) string {
+	return time.UnixMilli(ms).In(time.FixedZone("", offsetMinutes*60)).Format(time.RFC3339)
+}
+
+// ParseDate parses an ISO date (2025-11-21) or timestamp
+// (2025-11-21T18:22:11Z) into UTC milliseconds.
+func ParseDate(s string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UnixMilli(), nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// ParseDateUntil is ParseDate for an inclusive upper bound: a date
+// without a time covers the whole day, so it resolves to the last
+// millisecond before the next midnight.
+func ParseDateUntil(s string) (int64, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UnixMilli(), nil
+	}
+
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.AddDate(0, 0, 1).UnixMilli() - 1, nil
+}
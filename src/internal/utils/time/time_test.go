@@ -0,0 +1,48 @@
+package time
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC).UnixMilli()
+	nextDay := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC).UnixMilli()
+	stamp := time.Date(2026, 10, 19, 18, 22, 11, 0, time.UTC).UnixMilli()
+
+	tests := []struct {
+		in        string
+		since     int64
+		until     int64
+		wantError bool
+	}{
+		{in: "2026-10-19", since: day, until: nextDay - 1},
+		{in: "2026-10-19T18:22:11Z", since: stamp, until: stamp},
+		{in: "2026-10-19T20:22:11+02:00", since: stamp, until: stamp},
+		{in: "2026-12-31", since: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC).UnixMilli(),
+			until: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli() - 1},
+		{in: "yesterday", wantError: true},
+		{in: "2026-13-01", wantError: true},
+		{in: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		since, err := ParseDate(tt.in)
+		if tt.wantError {
+			if err == nil {
+				t.Errorf("ParseDate(%q) = %d, want an error", tt.in, since)
+			}
+			if until, err := ParseDateUntil(tt.in); err == nil {
+				t.Errorf("ParseDateUntil(%q) = %d, want an error", tt.in, until)
+			}
+			continue
+		}
+
+		if err != nil || since != tt.since {
+			t.Errorf("ParseDate(%q) = %d, %v, want %d", tt.in, since, err, tt.since)
+		}
+		if until, err := ParseDateUntil(tt.in); err != nil || until != tt.until {
+			t.Errorf("ParseDateUntil(%q) = %d, %v, want %d", tt.in, until, err, tt.until)
+		}
+	}
+}